@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+	"client-server-api/internal/server/retention"
+)
+
+const usage = `uso:
+  dbadmin migrate up
+  dbadmin migrate down [passos]
+  dbadmin migrate status
+  dbadmin compact
+  dbadmin retention [--dry-run]`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal("Erro ao carregar configuração:", err)
+	}
+	cfg.Database.AutoMigrate = false
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch os.Args[1] {
+	case "migrate":
+		err = withRepository(cfg.Database, func(repo repository.Store) error {
+			migratable, ok := repo.(repository.Migratable)
+			if !ok {
+				return fmt.Errorf("o driver %s não usa migrações", cfg.Database.Driver)
+			}
+			return runMigrate(ctx, migratable.Migrator(), os.Args[2:])
+		})
+	case "compact":
+		err = withRepository(cfg.Database, func(repo repository.Store) error {
+			return runCompact(ctx, repo)
+		})
+	case "retention":
+		err = withRepository(cfg.Database, func(repo repository.Store) error {
+			return runRetention(ctx, repo, cfg.Database.Retention, os.Args[2:])
+		})
+	default:
+		err = fmt.Errorf("comando desconhecido: %s\n%s", os.Args[1], usage)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func withRepository(cfg config.DatabaseConfig, fn func(repo repository.Store) error) error {
+	repo, err := repository.NewRepository(cfg)
+	if err != nil {
+		return fmt.Errorf("erro ao criar repositório: %w", err)
+	}
+	defer repo.Close()
+
+	return fn(repo)
+}
+
+func runCompact(ctx context.Context, repo repository.Store) error {
+	compacter, ok := repo.(repository.Compacter)
+	if !ok {
+		return fmt.Errorf("o repositório configurado não suporta compactação")
+	}
+
+	removed, err := compacter.Compact(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removidas %d cotações duplicadas\n", removed)
+	return nil
+}
+
+func runRetention(ctx context.Context, repo repository.Store, cfg config.RetentionConfig, args []string) error {
+	retainer, ok := repo.(repository.Retainer)
+	if !ok {
+		return fmt.Errorf("o repositório configurado não suporta retenção")
+	}
+
+	for _, arg := range args {
+		if arg != "--dry-run" {
+			return fmt.Errorf("argumento desconhecido: %s\n%s", arg, usage)
+		}
+		cfg.DryRun = true
+	}
+
+	result, err := retention.NewScheduler(retainer, cfg).RunOnce(ctx)
+	if err != nil {
+		return err
+	}
+
+	verb := "Removidas"
+	if result.DryRun {
+		verb = "Seriam removidas"
+	}
+	fmt.Printf("%s %d cotações anteriores a %s (%d agregados por hora)\n",
+		verb, result.Deleted, result.Cutoff.Format(time.RFC3339), result.Downsampled)
+
+	if vacuumer, ok := repo.(repository.Vacuumer); ok && !result.DryRun {
+		if err := vacuumer.Vacuum(ctx); err != nil {
+			return err
+		}
+		fmt.Println("VACUUM concluído")
+	}
+	return nil
+}
+
+func runMigrate(ctx context.Context, migrator *repository.Migrator, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("Aplicada %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Nenhuma migração pendente")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("número de passos inválido: %s", args[1])
+			}
+			steps = parsed
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("Revertida %03d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Nenhuma migração para reverter")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pendente"
+			if status.Applied {
+				applied = "aplicada em " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%03d_%s: %s\n", status.Version, status.Name, applied)
+		}
+	default:
+		return fmt.Errorf("subcomando desconhecido: %s\n%s", args[0], usage)
+	}
+
+	return nil
+}
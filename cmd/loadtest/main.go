@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"client-server-api/internal/external"
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+	"client-server-api/internal/server/handler"
+	"client-server-api/internal/server/service"
+	"client-server-api/pkg/models"
+)
+
+type countingRepository struct {
+	repository.CotacaoRepository
+	saves atomic.Int64
+}
+
+func (r *countingRepository) Save(ctx context.Context, cotacao *models.Cotacao) error {
+	r.saves.Add(1)
+	return r.CotacaoRepository.Save(ctx, cotacao)
+}
+
+func main() {
+	clients := flag.Int("clients", 500, "número de requisições concorrentes")
+	latency := flag.Duration("latency", 100*time.Millisecond, "latência simulada da API externa")
+	flag.Parse()
+
+	var upstreamCalls atomic.Int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls.Add(1)
+		time.Sleep(*latency)
+		json.NewEncoder(w).Encode(models.AwesomeAPIResponse{
+			"USDBRL": {
+				Code:      "USD",
+				Codein:    "BRL",
+				Bid:       "5.4133",
+				Ask:       "5.4143",
+				Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			},
+		})
+	}))
+	defer upstream.Close()
+
+	dir, err := os.MkdirTemp("", "loadtest")
+	if err != nil {
+		log.Fatal("Erro ao criar diretório temporário:", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sqliteRepo, err := repository.NewSQLiteRepository(config.DatabaseConfig{
+		DSN:            filepath.Join(dir, "cotacoes.db"),
+		MaxConnections: 10,
+		Timeout:        time.Second,
+		AutoMigrate:    true,
+	})
+	if err != nil {
+		log.Fatal("Erro ao criar repositório:", err)
+	}
+	defer sqliteRepo.Close()
+	repo := &countingRepository{CotacaoRepository: sqliteRepo}
+
+	apiClient := external.NewAwesomeAPIClient(config.APIConfig{
+		BaseURL: upstream.URL,
+		Timeout: 5 * time.Second,
+	})
+	cotacaoService := service.NewCotacaoService(apiClient, repo, config.CacheConfig{}, config.PollerConfig{})
+	cotacaoHandler := handler.NewCotacaoHandler(cotacaoService, []models.Pair{models.DefaultPair})
+
+	server := httptest.NewServer(http.HandlerFunc(cotacaoHandler.GetCotacao))
+	defer server.Close()
+
+	httpClient := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: *clients},
+	}
+
+	var (
+		wg       sync.WaitGroup
+		failures atomic.Int64
+	)
+	start := make(chan struct{})
+	for i := 0; i < *clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			resp, err := httpClient.Get(server.URL)
+			if err != nil {
+				failures.Add(1)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				failures.Add(1)
+			}
+		}()
+	}
+
+	began := time.Now()
+	close(start)
+	wg.Wait()
+
+	fmt.Printf("Requisições: %d (falhas: %d) em %s\n", *clients, failures.Load(), time.Since(began).Round(time.Millisecond))
+	fmt.Printf("Chamadas à API externa: %d\n", upstreamCalls.Load())
+	fmt.Printf("Inserts no banco: %d\n", repo.saves.Load())
+}
@@ -13,7 +13,10 @@ import (
 	"client-server-api/internal/repository"
 	"client-server-api/internal/server/config"
 	"client-server-api/internal/server/handler"
+	"client-server-api/internal/server/poller"
+	"client-server-api/internal/server/retention"
 	"client-server-api/internal/server/service"
+	"client-server-api/internal/server/stream"
 )
 
 func main() {
@@ -22,24 +25,92 @@ func main() {
 		log.Fatal("Erro ao carregar configuração:", err)
 	}
 
-	apiClient := external.NewAwesomeAPIClient(cfg.API)
+	apiClient, err := external.NewClientFromConfig(cfg.API)
+	if err != nil {
+		log.Fatal("Erro ao configurar provedores de cotação:", err)
+	}
 
-	repo, err := repository.NewSQLiteRepository(cfg.Database)
+	repo, err := repository.NewRepository(cfg.Database)
 	if err != nil {
 		log.Fatal("Erro ao criar repositório:", err)
 	}
 	defer repo.Close()
 
-	cotacaoService := service.NewCotacaoService(apiClient, repo)
+	if checker, ok := repo.(repository.SchemaChecker); ok {
+		if err := checker.CheckSchema(context.Background()); err != nil {
+			log.Fatal("Esquema do banco desatualizado:", err)
+		}
+	}
+
+	broker := stream.NewBroker(cfg.Stream)
+
+	var (
+		store  repository.CotacaoRepository = repository.NewPublishingRepository(repo, broker)
+		writer *repository.WriteBehindRepository
+	)
+	if cfg.Database.WriteBehind.Enabled {
+		log.Printf("Gravação assíncrona em lotes de até %d cotações\n", cfg.Database.WriteBehind.BatchSize)
+		writer = repository.NewWriteBehindRepository(store, cfg.Database.WriteBehind)
+		store = writer
+	}
+
+	cotacaoService := service.NewCotacaoService(apiClient, store, cfg.Cache, cfg.Poller)
+
+	cotacaoPoller := poller.NewPoller(apiClient, store, cfg.API.Pairs, cfg.Poller)
+	pollerCtx, stopPoller := context.WithCancel(context.Background())
+	defer stopPoller()
+	if cfg.Poller.Enabled {
+		log.Printf("Atualizando cotações a cada %s\n", cfg.Poller.Interval)
+		cotacaoPoller.Start(pollerCtx)
+	}
+
+	var retentionScheduler *retention.Scheduler
+	retentionCtx, stopRetention := context.WithCancel(context.Background())
+	defer stopRetention()
+	if cfg.Database.Retention.Enabled {
+		if retainer, ok := repo.(repository.Retainer); ok {
+			log.Printf("Retendo cotações por %s, verificando a cada %s\n", cfg.Database.Retention.MaxAge, cfg.Database.Retention.Interval)
+			retentionScheduler = retention.NewScheduler(retainer, cfg.Database.Retention)
+			retentionScheduler.Start(retentionCtx)
+		} else {
+			log.Println("Retenção não suportada pelo repositório configurado")
+		}
+	}
 
-	cotacaoHandler := handler.NewCotacaoHandler(cotacaoService)
+	cotacaoHandler := handler.NewCotacaoHandler(cotacaoService, cfg.API.Pairs)
 
 	http.HandleFunc("/cotacao", cotacaoHandler.GetCotacao)
+	http.HandleFunc("/cotacao/{pair}", cotacaoHandler.GetCotacao)
+	http.HandleFunc("/cotacao/full", cotacaoHandler.GetCotacaoFull)
+	http.HandleFunc("/cotacao/{pair}/full", cotacaoHandler.GetCotacaoFull)
+	http.HandleFunc("/pares", cotacaoHandler.ListPares)
+	http.HandleFunc("/cotacoes", cotacaoHandler.ListCotacoes)
+	http.HandleFunc("/cotacoes/{id}", cotacaoHandler.GetCotacaoByID)
+	http.HandleFunc("/cotacoes/candles", cotacaoHandler.GetCandles)
+	http.HandleFunc("/status/cache", cotacaoHandler.GetCacheStats)
+
+	streamHandler := handler.NewStreamHandler(broker, cotacaoService, cfg.API.Pairs, cfg.Stream)
+	http.HandleFunc("/cotacao/stream", streamHandler.Stream)
+
+	conversionService := service.NewConversionService(cotacaoService, cfg.API.Pairs, cfg.Convert)
+	conversionHandler := handler.NewConversionHandler(conversionService)
+	http.HandleFunc("/convert", conversionHandler.Convert)
+
+	var breakers []*external.CircuitBreaker
+	if reporter, ok := apiClient.(external.BreakerReporter); ok {
+		breakers = reporter.Breakers()
+	}
+
+	statusHandler := handler.NewStatusHandler(cotacaoPoller, breakers, writer)
+	http.HandleFunc("/status/poller", statusHandler.GetPollerStatus)
+	http.HandleFunc("/status/circuit", statusHandler.GetCircuitStatus)
+	http.HandleFunc("/status/writer", statusHandler.GetWriterStatus)
 
 	server := &http.Server{
 		Addr:    ":" + cfg.Server.Port,
 		Handler: nil,
 	}
+	server.RegisterOnShutdown(broker.Close)
 
 	go func() {
 		log.Printf("Servidor iniciado na porta %s\n", cfg.Server.Port)
@@ -58,7 +129,25 @@ func main() {
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Erro ao encerrar servidor:", err)
+		log.Println("Erro ao encerrar servidor:", err)
+	}
+
+	if cfg.Poller.Enabled {
+		stopPoller()
+		cotacaoPoller.Wait()
+	}
+
+	if retentionScheduler != nil {
+		stopRetention()
+		retentionScheduler.Wait()
+	}
+
+	if writer != nil {
+		if err := writer.Shutdown(ctx); err != nil {
+			log.Println("Erro ao gravar cotações pendentes:", err)
+		}
+		stats := writer.Stats()
+		log.Printf("Cotações gravadas: %d, descartadas: %d, com erro: %d\n", stats.Written, stats.Dropped, stats.Failed)
 	}
 
 	log.Println("Servidor encerrado com sucesso")
@@ -2,4 +2,7 @@ module client-server-api
 
 go 1.25.0
 
-require github.com/mattn/go-sqlite3 v1.14.32
+require (
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.32
+)
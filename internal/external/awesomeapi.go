@@ -4,8 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"client-server-api/internal/server/config"
@@ -13,70 +14,178 @@ import (
 	"client-server-api/pkg/models"
 )
 
+const maxClockSkew = 5 * time.Minute
+
 type AwesomeAPIClient struct {
 	baseURL string
 	client  *http.Client
 	timeout time.Duration
+	retry   config.RetryConfig
+	maxAge  time.Duration
 }
 
 func NewAwesomeAPIClient(cfg config.APIConfig) *AwesomeAPIClient {
 	return &AwesomeAPIClient{
-		baseURL: cfg.BaseURL,
+		baseURL: awesomeAPIBaseURL(cfg.BaseURL),
 		client: &http.Client{
 			Timeout: cfg.Timeout,
 		},
 		timeout: cfg.Timeout,
+		retry:   cfg.Retry,
+		maxAge:  cfg.MaxQuoteAge,
 	}
 }
 
-func (c *AwesomeAPIClient) FetchUSD(ctx context.Context) (*models.Cotacao, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL, nil)
-	if err != nil {
-		return nil, errors.ErroAPI(err)
+func awesomeAPIBaseURL(value string) string {
+	baseURL := strings.TrimRight(value, "/")
+
+	i := strings.LastIndex(baseURL, "/")
+	if i < 0 {
+		return baseURL
+	}
+	if pairs, err := models.ParsePairs(baseURL[i+1:]); err != nil || len(pairs) == 0 {
+		return baseURL
 	}
 
-	resp, err := c.client.Do(req)
+	log.Printf("API_BASE_URL não deve incluir o par (%s); usando %s\n", baseURL[i+1:], baseURL[:i])
+	return baseURL[:i]
+}
+
+func (c *AwesomeAPIClient) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	cotacoes, err := c.FetchBatch(ctx, []models.Pair{pair})
 	if err != nil {
-		if err == context.DeadlineExceeded {
-			return nil, errors.ErroTimeoutContext("chamada à API", err)
-		}
-		return nil, errors.ErroAPI(err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.ErroAPI(fmt.Errorf("status %d: %s", resp.StatusCode, resp.Status))
+	return cotacoes[0], nil
+}
+
+func (c *AwesomeAPIClient) FetchBatch(ctx context.Context, pairs []models.Pair) ([]*models.Cotacao, error) {
+	if len(pairs) == 0 {
+		return nil, errors.ErroValidacao("nenhum par de moedas informado")
+	}
+
+	names := make([]string, len(pairs))
+	for i, pair := range pairs {
+		names[i] = pair.String()
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := fetchBody(ctx, c.client, c.baseURL+"/"+strings.Join(names, ","), c.retry)
 	if err != nil {
-		return nil, errors.ErroAPI(err)
+		return nil, err
 	}
 
-	var dolarResponse models.DolarResponse
-	if err := json.Unmarshal(body, &dolarResponse); err != nil {
+	var apiResponse models.AwesomeAPIResponse
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
 		return nil, errors.ErroAPI(fmt.Errorf("erro ao fazer parse do JSON: %w", err))
 	}
 
-	cotacao := &models.Cotacao{
-		Code:       dolarResponse.USDBRL.Code,
-		Codein:     dolarResponse.USDBRL.Codein,
-		Name:       dolarResponse.USDBRL.Name,
-		High:       dolarResponse.USDBRL.High,
-		Low:        dolarResponse.USDBRL.Low,
-		VarBid:     dolarResponse.USDBRL.VarBid,
-		PctChange:  dolarResponse.USDBRL.PctChange,
-		Bid:        dolarResponse.USDBRL.Bid,
-		Ask:        dolarResponse.USDBRL.Ask,
-		Timestamp:  dolarResponse.USDBRL.Timestamp,
-		CreateDate: dolarResponse.USDBRL.CreateDate,
-		CreatedAt:  time.Now(),
-	}
-
-	if cotacao.Bid == "" {
+	cotacoes, err := cotacoesFromResponse(apiResponse, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cotacao := range cotacoes {
+		if err := validateQuoteTime(cotacao, c.maxAge); err != nil {
+			return nil, err
+		}
+	}
+
+	return cotacoes, nil
+}
+
+func validateQuoteTime(cotacao *models.Cotacao, maxAge time.Duration) error {
+	if cotacao.Timestamp.IsZero() {
+		return errors.ErroValidacao("timestamp da cotação ausente")
+	}
+
+	now := time.Now()
+	for _, quoteTime := range []time.Time{cotacao.Timestamp, cotacao.CreateDate} {
+		if quoteTime.IsZero() {
+			continue
+		}
+		if quoteTime.After(now.Add(maxClockSkew)) {
+			return errors.ErroValidacao("cotação com data no futuro: " + quoteTime.Format(time.RFC3339))
+		}
+		if maxAge > 0 && now.Sub(quoteTime) > maxAge {
+			return errors.ErroValidacao("cotação muito antiga: " + quoteTime.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+func cotacoesFromResponse(apiResponse models.AwesomeAPIResponse, pairs []models.Pair) ([]*models.Cotacao, error) {
+	cotacoes := make([]*models.Cotacao, 0, len(pairs))
+	for _, pair := range pairs {
+		info, ok := apiResponse[pair.Key()]
+		if !ok {
+			return nil, errors.ErroAPI(fmt.Errorf("par %s ausente na resposta", pair))
+		}
+
+		cotacao, err := newCotacao(pair, info)
+		if err != nil {
+			return nil, err
+		}
+		cotacoes = append(cotacoes, cotacao)
+	}
+
+	return cotacoes, nil
+}
+
+func newCotacao(pair models.Pair, info models.DolarInfo) (*models.Cotacao, error) {
+	if info.Bid == "" {
 		return nil, errors.ErroValidacao("bid não pode estar vazio")
 	}
 
+	bid, err := models.ParseDecimal(info.Bid)
+	if err != nil || bid.Sign() <= 0 {
+		return nil, errors.ErroValidacao("bid inválido: " + info.Bid)
+	}
+
+	cotacao := &models.Cotacao{
+		Code:      info.Code,
+		Codein:    info.Codein,
+		Name:      info.Name,
+		Bid:       bid,
+		CreatedAt: time.Now(),
+	}
+
+	if cotacao.Timestamp, err = models.ParseUnixTimestamp(info.Timestamp); err != nil {
+		return nil, errors.ErroValidacao(err.Error())
+	}
+	if cotacao.CreateDate, err = models.ParseCreateDate(info.CreateDate); err != nil {
+		return nil, errors.ErroValidacao(err.Error())
+	}
+
+	fields := []struct {
+		name   string
+		value  string
+		target *models.Decimal
+	}{
+		{"high", info.High, &cotacao.High},
+		{"low", info.Low, &cotacao.Low},
+		{"varBid", info.VarBid, &cotacao.VarBid},
+		{"pctChange", info.PctChange, &cotacao.PctChange},
+		{"ask", info.Ask, &cotacao.Ask},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		parsed, err := models.ParseDecimal(field.value)
+		if err != nil {
+			return nil, errors.ErroValidacao(field.name + " inválido: " + field.value)
+		}
+		*field.target = parsed
+	}
+
+	if cotacao.Code == "" {
+		cotacao.Code = pair.Code
+	}
+	if cotacao.Codein == "" {
+		cotacao.Codein = pair.Codein
+	}
+
 	return cotacao, nil
 }
-
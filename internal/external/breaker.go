@@ -0,0 +1,160 @@
+package external
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+type BreakerStatus struct {
+	Provider string    `json:"provider"`
+	State    string    `json:"state"`
+	Failures int       `json:"failures"`
+	OpenedAt time.Time `json:"opened_at,omitzero"`
+	RetryAt  time.Time `json:"retry_at,omitzero"`
+}
+
+type CircuitBreaker struct {
+	name             string
+	client           ExchangeRateClient
+	failureThreshold int
+	coolDown         time.Duration
+	halfOpenMax      int
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	inFlight int
+}
+
+type BreakerReporter interface {
+	Breakers() []*CircuitBreaker
+}
+
+func NewCircuitBreaker(name string, client ExchangeRateClient, cfg config.BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:             name,
+		client:           client,
+		failureThreshold: max(cfg.FailureThreshold, 1),
+		coolDown:         cfg.CoolDown,
+		halfOpenMax:      max(cfg.HalfOpenMaxRequests, 1),
+		state:            BreakerClosed,
+	}
+}
+
+func (b *CircuitBreaker) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	cotacao, err := b.client.Fetch(ctx, pair)
+	b.record(ctx, err)
+
+	return cotacao, err
+}
+
+func (b *CircuitBreaker) FetchBatch(ctx context.Context, pairs []models.Pair) ([]*models.Cotacao, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+
+	cotacoes, err := fetchBatch(ctx, b.client, pairs)
+	b.record(ctx, err)
+
+	return cotacoes, err
+}
+
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		Provider: b.name,
+		State:    b.state,
+		Failures: b.failures,
+	}
+	if b.state != BreakerClosed {
+		status.OpenedAt = b.openedAt
+		status.RetryAt = b.openedAt.Add(b.coolDown)
+	}
+
+	return status
+}
+
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.coolDown {
+		b.state = BreakerHalfOpen
+		b.inFlight = 0
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		return errors.ErroCircuitoAberto(b.name)
+	case BreakerHalfOpen:
+		if b.inFlight >= b.halfOpenMax {
+			return errors.ErroCircuitoAberto(b.name)
+		}
+		b.inFlight++
+	}
+
+	return nil
+}
+
+func (b *CircuitBreaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.inFlight--
+	}
+
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	if ctx.Err() != nil || !isUpstreamFailure(err) {
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func isUpstreamFailure(err error) bool {
+	var appErr *errors.AppError
+	if !errors.As(err, &appErr) {
+		return true
+	}
+
+	return appErr.Code == "API_ERROR" || appErr.Code == "TIMEOUT"
+}
+
+func breakersOf(providers []Provider) []*CircuitBreaker {
+	var breakers []*CircuitBreaker
+	for _, provider := range providers {
+		if breaker, ok := provider.Client.(*CircuitBreaker); ok {
+			breakers = append(breakers, breaker)
+		}
+	}
+
+	return breakers
+}
@@ -0,0 +1,176 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type ConsensusClient struct {
+	providers    []Provider
+	maxDeviation float64
+	minProviders int
+}
+
+type consensusQuote struct {
+	provider string
+	cotacao  *models.Cotacao
+	bid      models.Decimal
+	ask      models.Decimal
+}
+
+func NewConsensusClient(maxDeviation float64, minProviders int, providers ...Provider) *ConsensusClient {
+	if minProviders < 1 {
+		minProviders = 1
+	}
+
+	return &ConsensusClient{
+		providers:    providers,
+		maxDeviation: maxDeviation,
+		minProviders: minProviders,
+	}
+}
+
+func (c *ConsensusClient) Providers() []string {
+	names := make([]string, len(c.providers))
+	for i, provider := range c.providers {
+		names[i] = provider.Name
+	}
+
+	return names
+}
+
+func (c *ConsensusClient) Breakers() []*CircuitBreaker {
+	return breakersOf(c.providers)
+}
+
+func (c *ConsensusClient) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		quotes  []consensusQuote
+		lastErr error
+	)
+
+	for _, provider := range c.providers {
+		wg.Add(1)
+		go func(provider Provider) {
+			defer wg.Done()
+
+			quote, err := fetchConsensusQuote(ctx, provider, pair)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("Provedor %s falhou para %s: %v\n", provider.Name, pair, err)
+				lastErr = err
+				return
+			}
+			quotes = append(quotes, quote)
+		}(provider)
+	}
+	wg.Wait()
+
+	if len(quotes) == 0 {
+		if ctx.Err() != nil {
+			return nil, errors.ErroTimeoutContext("chamada à API", ctx.Err())
+		}
+		if lastErr == nil {
+			lastErr = errors.ErroAPI(fmt.Errorf("nenhum provedor de cotação configurado"))
+		}
+		return nil, lastErr
+	}
+
+	median := medianOf(quotes, func(q consensusQuote) models.Decimal { return q.bid })
+	accepted := make([]consensusQuote, 0, len(quotes))
+	for _, quote := range quotes {
+		deviation := math.Abs(quote.bid.Sub(median).Float64()) / median.Float64() * 100
+		if c.maxDeviation > 0 && deviation > c.maxDeviation {
+			log.Printf("Provedor %s descartado para %s: desvio de %.2f%% da mediana\n", quote.provider, pair, deviation)
+			continue
+		}
+		accepted = append(accepted, quote)
+	}
+
+	if len(accepted) < c.minProviders {
+		return nil, errors.ErroAPI(fmt.Errorf("consenso insuficiente para %s: %d de %d provedores", pair, len(accepted), c.minProviders))
+	}
+
+	sort.Slice(accepted, func(i, j int) bool { return accepted[i].provider < accepted[j].provider })
+
+	bid := medianOf(accepted, func(q consensusQuote) models.Decimal { return q.bid })
+	ask := medianOf(accepted, func(q consensusQuote) models.Decimal { return q.ask })
+
+	closest := accepted[0]
+	names := make([]string, len(accepted))
+	minBid, maxBid := accepted[0].bid, accepted[0].bid
+	for i, quote := range accepted {
+		names[i] = quote.provider
+		if quote.bid.Cmp(minBid) < 0 {
+			minBid = quote.bid
+		}
+		if quote.bid.Cmp(maxBid) > 0 {
+			maxBid = quote.bid
+		}
+		if quote.bid.Sub(bid).Abs().Cmp(closest.bid.Sub(bid).Abs()) < 0 {
+			closest = quote
+		}
+	}
+
+	cotacao := *closest.cotacao
+	cotacao.Bid = bid
+	cotacao.Ask = ask
+	cotacao.Provider = strings.Join(names, ",")
+	cotacao.Spread = maxBid.Sub(minBid).String()
+
+	return &cotacao, nil
+}
+
+func fetchConsensusQuote(ctx context.Context, provider Provider, pair models.Pair) (consensusQuote, error) {
+	cotacao, err := provider.Client.Fetch(ctx, pair)
+	if err != nil {
+		return consensusQuote{}, err
+	}
+
+	if cotacao.Bid.Sign() <= 0 {
+		return consensusQuote{}, errors.ErroValidacao("bid inválido: " + cotacao.Bid.String())
+	}
+
+	ask := cotacao.Ask
+	if ask.Sign() <= 0 {
+		ask = cotacao.Bid
+	}
+
+	return consensusQuote{
+		provider: provider.Name,
+		cotacao:  cotacao,
+		bid:      cotacao.Bid,
+		ask:      ask,
+	}, nil
+}
+
+func medianOf(quotes []consensusQuote, value func(consensusQuote) models.Decimal) models.Decimal {
+	values := make([]models.Decimal, len(quotes))
+	places := 0
+	for i, quote := range quotes {
+		values[i] = value(quote)
+		places = max(places, values[i].Places())
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		sum := new(big.Rat).Add(values[middle-1].Rat(), values[middle].Rat())
+		return models.DecimalFromRat(sum.Quo(sum, big.NewRat(2, 1)), places)
+	}
+
+	return values[middle]
+}
@@ -0,0 +1,108 @@
+package external
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+var ecbLocation = models.MustLoadLocation("Europe/Berlin")
+
+const ecbRatePlaces = 8
+
+type ECBClient struct {
+	url    string
+	client *http.Client
+	retry  config.RetryConfig
+}
+
+func NewECBClient(cfg config.APIConfig) *ECBClient {
+	return &ECBClient{
+		url: cfg.ECBURL,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		retry: cfg.Retry,
+	}
+}
+
+func (c *ECBClient) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	cotacoes, err := c.FetchBatch(ctx, []models.Pair{pair})
+	if err != nil {
+		return nil, err
+	}
+
+	return cotacoes[0], nil
+}
+
+func (c *ECBClient) FetchBatch(ctx context.Context, pairs []models.Pair) ([]*models.Cotacao, error) {
+	body, err := fetchBody(ctx, c.client, c.url, c.retry)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return nil, errors.ErroAPI(fmt.Errorf("erro ao fazer parse do XML: %w", err))
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", envelope.Cube.Cube.Time, ecbLocation)
+	if err != nil {
+		return nil, errors.ErroAPI(fmt.Errorf("data inválida no XML: %w", err))
+	}
+
+	referenceTime := day.Add(16 * time.Hour)
+
+	rates := map[string]models.Decimal{"EUR": models.MustParseDecimal("1")}
+	for _, rate := range envelope.Cube.Cube.Rates {
+		value, err := models.ParseDecimal(rate.Rate)
+		if err != nil || value.Sign() <= 0 {
+			return nil, errors.ErroAPI(fmt.Errorf("taxa inválida para %s: %q", rate.Currency, rate.Rate))
+		}
+		rates[rate.Currency] = value
+	}
+
+	cotacoes := make([]*models.Cotacao, 0, len(pairs))
+	for _, pair := range pairs {
+		from, okFrom := rates[pair.Code]
+		to, okTo := rates[pair.Codein]
+		if !okFrom || !okTo {
+			return nil, errors.ErroAPI(fmt.Errorf("par %s ausente na resposta", pair))
+		}
+
+		value := models.DecimalFromRat(new(big.Rat).Quo(to.Rat(), from.Rat()), ecbRatePlaces).Trim()
+		cotacoes = append(cotacoes, &models.Cotacao{
+			Code:       pair.Code,
+			Codein:     pair.Codein,
+			Name:       pair.Code + "/" + pair.Codein,
+			High:       value,
+			Low:        value,
+			Bid:        value,
+			Ask:        value,
+			Timestamp:  referenceTime.UTC(),
+			CreateDate: referenceTime.In(models.SaoPaulo),
+			CreatedAt:  time.Now(),
+		})
+	}
+
+	return cotacoes, nil
+}
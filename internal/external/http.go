@@ -0,0 +1,128 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+)
+
+func fetchBody(ctx context.Context, client *http.Client, url string, retry config.RetryConfig) ([]byte, error) {
+	attempts := max(retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		body, retryAfter, transient, err := fetchOnce(ctx, client, url)
+		if err == nil {
+			if attempt > 1 {
+				log.Printf("Chamada a %s bem-sucedida após %d tentativas\n", url, attempt)
+			}
+			return body, nil
+		}
+
+		if !transient || attempt >= attempts {
+			if attempt > 1 {
+				log.Printf("Chamada a %s falhou após %d tentativas: %v\n", url, attempt, err)
+			}
+			return nil, err
+		}
+
+		delay := backoff(retry, attempt)
+		if retryAfter > 0 {
+			if retry.MaxRetryAfter > 0 && retryAfter > retry.MaxRetryAfter {
+				log.Printf("Chamada a %s falhou após %d tentativas, Retry-After de %s excede o limite de %s: %v\n",
+					url, attempt, retryAfter, retry.MaxRetryAfter, err)
+				return nil, err
+			}
+			delay = retryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			log.Printf("Chamada a %s falhou após %d tentativas, sem tempo para nova tentativa: %v\n", url, attempt, err)
+			return nil, err
+		}
+
+		log.Printf("Tentativa %d de %d para %s falhou, nova tentativa em %s: %v\n", attempt, attempts, url, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func fetchOnce(ctx context.Context, client *http.Client, url string) ([]byte, time.Duration, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, false, errors.ErroAPI(err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		transient, err := requestError(ctx, err)
+		return nil, 0, transient, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		transient := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), transient,
+			errors.ErroAPI(fmt.Errorf("status %d: %s", resp.StatusCode, resp.Status))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		transient, err := requestError(ctx, err)
+		return nil, 0, transient, err
+	}
+
+	return body, 0, false, nil
+}
+
+func requestError(ctx context.Context, err error) (bool, error) {
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, errors.ErroTimeoutContext("chamada à API", err)
+	}
+	return ctx.Err() == nil, errors.ErroAPI(err)
+}
+
+func backoff(retry config.RetryConfig, attempt int) time.Duration {
+	delay := retry.BaseDelay
+	for i := 1; i < attempt && delay < retry.MaxDelay; i++ {
+		delay *= 2
+	}
+	if retry.MaxDelay > 0 {
+		delay = min(delay, retry.MaxDelay)
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+
+	return 0
+}
@@ -6,6 +6,10 @@ import (
 )
 
 type ExchangeRateClient interface {
-	FetchUSD(ctx context.Context) (*models.Cotacao, error)
+	Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error)
 }
 
+type BatchExchangeRateClient interface {
+	ExchangeRateClient
+	FetchBatch(ctx context.Context, pairs []models.Pair) ([]*models.Cotacao, error)
+}
@@ -0,0 +1,158 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type Provider struct {
+	Name   string
+	Client ExchangeRateClient
+}
+
+type Registry struct {
+	providers []Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	return &Registry{
+		providers: providers,
+	}
+}
+
+func NewClientFromConfig(cfg config.APIConfig) (ExchangeRateClient, error) {
+	providers, err := NewProvidersFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Mode {
+	case "fallback":
+		return NewRegistry(providers...), nil
+	case "consensus":
+		return NewConsensusClient(cfg.ConsensusMaxDeviation, cfg.ConsensusMinProviders, providers...), nil
+	default:
+		return nil, fmt.Errorf("modo de cotação desconhecido: %s", cfg.Mode)
+	}
+}
+
+func NewProvidersFromConfig(cfg config.APIConfig) ([]Provider, error) {
+	providers := make([]Provider, 0, len(cfg.Providers))
+	for _, name := range cfg.Providers {
+		client, err := NewProvider(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Breaker.Enabled {
+			client = NewCircuitBreaker(name, client, cfg.Breaker)
+		}
+		providers = append(providers, Provider{Name: name, Client: client})
+	}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("nenhum provedor de cotação configurado")
+	}
+
+	return providers, nil
+}
+
+func NewProvider(name string, cfg config.APIConfig) (ExchangeRateClient, error) {
+	switch name {
+	case "awesomeapi":
+		return NewAwesomeAPIClient(cfg), nil
+	case "ecb":
+		return NewECBClient(cfg), nil
+	case "static":
+		if cfg.StaticFile == "" {
+			return nil, fmt.Errorf("provedor static requer API_STATIC_FILE")
+		}
+		return NewStaticFileClient(cfg.StaticFile), nil
+	default:
+		return nil, fmt.Errorf("provedor de cotação desconhecido: %s", name)
+	}
+}
+
+func (r *Registry) Providers() []string {
+	names := make([]string, len(r.providers))
+	for i, provider := range r.providers {
+		names[i] = provider.Name
+	}
+
+	return names
+}
+
+func (r *Registry) Breakers() []*CircuitBreaker {
+	return breakersOf(r.providers)
+}
+
+func (r *Registry) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	var lastErr error
+	for _, provider := range r.providers {
+		cotacao, err := provider.Client.Fetch(ctx, pair)
+		if err == nil {
+			cotacao.Provider = provider.Name
+			return cotacao, nil
+		}
+
+		log.Printf("Provedor %s falhou para %s: %v\n", provider.Name, pair, err)
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return nil, errors.ErroTimeoutContext("chamada à API", ctx.Err())
+		}
+	}
+
+	if lastErr == nil {
+		return nil, errors.ErroAPI(fmt.Errorf("nenhum provedor de cotação configurado"))
+	}
+
+	return nil, lastErr
+}
+
+func (r *Registry) FetchBatch(ctx context.Context, pairs []models.Pair) ([]*models.Cotacao, error) {
+	var lastErr error
+	for _, provider := range r.providers {
+		cotacoes, err := fetchBatch(ctx, provider.Client, pairs)
+		if err == nil {
+			for _, cotacao := range cotacoes {
+				cotacao.Provider = provider.Name
+			}
+			return cotacoes, nil
+		}
+
+		log.Printf("Provedor %s falhou para %d pares: %v\n", provider.Name, len(pairs), err)
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return nil, errors.ErroTimeoutContext("chamada à API", ctx.Err())
+		}
+	}
+
+	if lastErr == nil {
+		return nil, errors.ErroAPI(fmt.Errorf("nenhum provedor de cotação configurado"))
+	}
+
+	return nil, lastErr
+}
+
+func fetchBatch(ctx context.Context, client ExchangeRateClient, pairs []models.Pair) ([]*models.Cotacao, error) {
+	if batch, ok := client.(BatchExchangeRateClient); ok {
+		return batch.FetchBatch(ctx, pairs)
+	}
+
+	cotacoes := make([]*models.Cotacao, 0, len(pairs))
+	for _, pair := range pairs {
+		cotacao, err := client.Fetch(ctx, pair)
+		if err != nil {
+			return nil, err
+		}
+		cotacoes = append(cotacoes, cotacao)
+	}
+
+	return cotacoes, nil
+}
@@ -0,0 +1,48 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type StaticFileClient struct {
+	path string
+}
+
+func NewStaticFileClient(path string) *StaticFileClient {
+	return &StaticFileClient{
+		path: path,
+	}
+}
+
+func (c *StaticFileClient) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	cotacoes, err := c.FetchBatch(ctx, []models.Pair{pair})
+	if err != nil {
+		return nil, err
+	}
+
+	return cotacoes[0], nil
+}
+
+func (c *StaticFileClient) FetchBatch(ctx context.Context, pairs []models.Pair) ([]*models.Cotacao, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.ErroTimeoutContext("leitura do arquivo de cotações", err)
+	}
+
+	body, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, errors.ErroAPI(err)
+	}
+
+	var apiResponse models.AwesomeAPIResponse
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, errors.ErroAPI(fmt.Errorf("erro ao fazer parse do JSON: %w", err))
+	}
+
+	return cotacoesFromResponse(apiResponse, pairs)
+}
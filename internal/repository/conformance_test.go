@@ -0,0 +1,609 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type check struct {
+	name string
+	run  func(ctx context.Context, repo CotacaoRepository) error
+}
+
+var checks = []check{
+	{"save_assigns_increasing_ids", checkSaveAssignsIDs},
+	{"save_rejects_missing_pair", checkSaveRejectsMissingPair},
+	{"find_by_id_round_trip", checkFindByIDRoundTrip},
+	{"find_by_id_not_found", checkFindByIDNotFound},
+	{"find_latest", checkFindLatest},
+	{"list_pagination", checkListPagination},
+	{"list_time_filter", checkListTimeFilter},
+	{"candles", checkCandles},
+	{"save_batch", checkSaveBatch},
+	{"concurrent_saves", checkConcurrentSaves},
+	{"expired_context", checkExpiredContext},
+}
+
+var ignoreChecks = []check{
+	{"ignore_keeps_first", checkIgnoreKeepsFirst},
+	{"dedup_distinct_keys", checkDedupDistinctKeys},
+}
+
+var upsertChecks = []check{
+	{"upsert_keeps_id", checkUpsertKeepsID},
+	{"dedup_distinct_keys", checkDedupDistinctKeys},
+	{"upsert_batch", checkUpsertBatch},
+}
+
+type conformanceBackend struct {
+	name string
+	open func(t *testing.T, dedup string) Store
+}
+
+func TestConformance(t *testing.T) {
+	backends := []conformanceBackend{
+		{name: "memory", open: openMemoryConformance},
+		{name: config.DriverSQLite, open: openSQLiteConformance},
+		{name: config.DriverPostgres, open: openPostgresConformance},
+	}
+	policies := []struct {
+		dedup  string
+		checks []check
+	}{
+		{config.DedupIgnore, ignoreChecks},
+		{config.DedupUpsert, upsertChecks},
+	}
+
+	for _, backend := range backends {
+		for _, policy := range policies {
+			t.Run(backend.name+"/"+policy.dedup, func(t *testing.T) {
+				repo := backend.open(t, policy.dedup)
+				ctx := context.Background()
+
+				for _, c := range append(append([]check{}, checks...), policy.checks...) {
+					t.Run(c.name, func(t *testing.T) {
+						if err := c.run(ctx, repo); err != nil {
+							t.Fatal(err)
+						}
+					})
+				}
+			})
+		}
+	}
+}
+
+func openMemoryConformance(t *testing.T, dedup string) Store {
+	return NewMemoryRepository(config.DatabaseConfig{Timeout: time.Second, Dedup: dedup})
+}
+
+func openSQLiteConformance(t *testing.T, dedup string) Store {
+	return newSQLiteTestRepository(t, dedup)
+}
+
+func openPostgresConformance(t *testing.T, dedup string) Store {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN não definido")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("abrir postgres: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("conformance_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("criar schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	repo, err := NewPostgresRepository(config.DatabaseConfig{
+		Driver:         config.DriverPostgres,
+		DSN:            withSearchPath(dsn, schema),
+		MaxConnections: 2,
+		Timeout:        time.Second,
+		AutoMigrate:    true,
+		Dedup:          dedup,
+	})
+	if err != nil {
+		t.Fatalf("criar repositório postgres: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	return repo
+}
+
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		parsed, err := url.Parse(dsn)
+		if err == nil {
+			query := parsed.Query()
+			query.Set("search_path", schema)
+			parsed.RawQuery = query.Encode()
+			return parsed.String()
+		}
+	}
+	return dsn + " search_path=" + schema
+}
+
+var baseTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newCotacao(pair models.Pair, bid string, timestamp time.Time) *models.Cotacao {
+	return &models.Cotacao{
+		Code:       pair.Code,
+		Codein:     pair.Codein,
+		Name:       pair.String(),
+		High:       models.MustParseDecimal(bid),
+		Low:        models.MustParseDecimal(bid),
+		VarBid:     models.MustParseDecimal("0.0012"),
+		PctChange:  models.MustParseDecimal("0.02"),
+		Bid:        models.MustParseDecimal(bid),
+		Ask:        models.MustParseDecimal(bid),
+		Timestamp:  timestamp,
+		CreateDate: timestamp.In(models.SaoPaulo),
+		Provider:   "conformance",
+	}
+}
+
+func saveAll(ctx context.Context, repo CotacaoRepository, cotacoes ...*models.Cotacao) error {
+	for _, cotacao := range cotacoes {
+		if err := repo.Save(ctx, cotacao); err != nil {
+			return fmt.Errorf("salvar cotação: %w", err)
+		}
+	}
+	return nil
+}
+
+func expectCode(err error, code string) error {
+	var appErr *errors.AppError
+	if !errors.As(err, &appErr) {
+		return fmt.Errorf("esperado erro %s, obtido %v", code, err)
+	}
+	if appErr.Code != code {
+		return fmt.Errorf("esperado erro %s, obtido %s (%v)", code, appErr.Code, err)
+	}
+	return nil
+}
+
+func expectIDs(cotacoes []*models.Cotacao, ids ...int64) error {
+	if len(cotacoes) != len(ids) {
+		return fmt.Errorf("esperadas %d cotações, obtidas %d", len(ids), len(cotacoes))
+	}
+	for i, cotacao := range cotacoes {
+		if cotacao.ID != ids[i] {
+			return fmt.Errorf("posição %d: esperado id %d, obtido %d", i, ids[i], cotacao.ID)
+		}
+	}
+	return nil
+}
+
+func checkSaveAssignsIDs(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "BRL"}
+	first := newCotacao(pair, "1.0", baseTime)
+	second := newCotacao(pair, "1.1", baseTime.Add(time.Minute))
+	if err := saveAll(ctx, repo, first, second); err != nil {
+		return err
+	}
+
+	if first.ID <= 0 {
+		return fmt.Errorf("id não atribuído: %d", first.ID)
+	}
+	if second.ID <= first.ID {
+		return fmt.Errorf("ids não crescentes: %d, %d", first.ID, second.ID)
+	}
+	return nil
+}
+
+func checkSaveRejectsMissingPair(ctx context.Context, repo CotacaoRepository) error {
+	return expectCode(repo.Save(ctx, &models.Cotacao{Bid: models.MustParseDecimal("1")}), "VALIDATION_ERROR")
+}
+
+func checkFindByIDRoundTrip(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "USD"}
+	saved := newCotacao(pair, "5.4321", baseTime)
+	saved.Spread = "0.0010"
+	if err := saveAll(ctx, repo, saved); err != nil {
+		return err
+	}
+
+	found, err := repo.FindByID(ctx, saved.ID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case found.ID != saved.ID:
+		return fmt.Errorf("id: esperado %d, obtido %d", saved.ID, found.ID)
+	case found.Code != saved.Code || found.Codein != saved.Codein:
+		return fmt.Errorf("par: esperado %s-%s, obtido %s-%s", saved.Code, saved.Codein, found.Code, found.Codein)
+	case found.Name != saved.Name:
+		return fmt.Errorf("nome: esperado %q, obtido %q", saved.Name, found.Name)
+	case found.Bid.Cmp(saved.Bid) != 0 || found.Ask.Cmp(saved.Ask) != 0:
+		return fmt.Errorf("preço: esperado %s/%s, obtido %s/%s", saved.Bid, saved.Ask, found.Bid, found.Ask)
+	case found.VarBid.Cmp(saved.VarBid) != 0 || found.PctChange.Cmp(saved.PctChange) != 0:
+		return fmt.Errorf("variação: esperado %s/%s, obtido %s/%s", saved.VarBid, saved.PctChange, found.VarBid, found.PctChange)
+	case !found.Timestamp.Equal(saved.Timestamp):
+		return fmt.Errorf("timestamp: esperado %s, obtido %s", saved.Timestamp, found.Timestamp)
+	case !found.CreateDate.Equal(saved.CreateDate):
+		return fmt.Errorf("create_date: esperado %s, obtido %s", saved.CreateDate, found.CreateDate)
+	case found.Provider != saved.Provider || found.Spread != saved.Spread:
+		return fmt.Errorf("provedor: esperado %s/%s, obtido %s/%s", saved.Provider, saved.Spread, found.Provider, found.Spread)
+	case found.CreatedAt.IsZero():
+		return fmt.Errorf("created_at não preenchido")
+	case !found.CreatedAt.Equal(saved.CreatedAt):
+		return fmt.Errorf("created_at: esperado %s, obtido %s", saved.CreatedAt, found.CreatedAt)
+	}
+	return nil
+}
+
+func checkFindByIDNotFound(ctx context.Context, repo CotacaoRepository) error {
+	_, err := repo.FindByID(ctx, math.MaxInt64)
+	return expectCode(err, "NOT_FOUND")
+}
+
+func checkFindLatest(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "EUR"}
+	_, err := repo.FindLatest(ctx, pair)
+	if err := expectCode(err, "NOT_FOUND"); err != nil {
+		return fmt.Errorf("par sem cotações: %w", err)
+	}
+
+	older := newCotacao(pair, "6.0", baseTime.Add(time.Hour))
+	newer := newCotacao(pair, "6.1", baseTime)
+	other := newCotacao(models.Pair{Code: "XTS", Codein: "GBP"}, "7.0", baseTime)
+	if err := saveAll(ctx, repo, older, newer, other); err != nil {
+		return err
+	}
+
+	latest, err := repo.FindLatest(ctx, pair)
+	if err != nil {
+		return err
+	}
+	if latest.ID != newer.ID {
+		return fmt.Errorf("esperada a última inserida (id %d), obtido id %d", newer.ID, latest.ID)
+	}
+	return nil
+}
+
+func checkListPagination(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "JPY"}
+	cotacoes := []*models.Cotacao{
+		newCotacao(pair, "0.031", baseTime),
+		newCotacao(pair, "0.032", baseTime.Add(time.Minute)),
+		newCotacao(pair, "0.033", baseTime.Add(2*time.Minute)),
+	}
+	if err := saveAll(ctx, repo, cotacoes...); err != nil {
+		return err
+	}
+	ids := []int64{cotacoes[0].ID, cotacoes[1].ID, cotacoes[2].ID}
+
+	page, err := repo.List(ctx, ListFilter{Pair: &pair, Limit: 2})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(page, ids[0], ids[1]); err != nil {
+		return fmt.Errorf("primeira página: %w", err)
+	}
+
+	page, err = repo.List(ctx, ListFilter{Pair: &pair, AfterID: ids[1], Limit: 2})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(page, ids[2]); err != nil {
+		return fmt.Errorf("segunda página: %w", err)
+	}
+
+	page, err = repo.List(ctx, ListFilter{Pair: &pair, Descending: true, Limit: 2})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(page, ids[2], ids[1]); err != nil {
+		return fmt.Errorf("ordem decrescente: %w", err)
+	}
+
+	page, err = repo.List(ctx, ListFilter{Pair: &pair, Descending: true, AfterID: ids[1], Limit: 2})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(page, ids[0]); err != nil {
+		return fmt.Errorf("ordem decrescente, segunda página: %w", err)
+	}
+	return nil
+}
+
+func checkListTimeFilter(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "ARS"}
+	cotacoes := []*models.Cotacao{
+		newCotacao(pair, "0.0051", baseTime),
+		newCotacao(pair, "0.0052", baseTime.Add(time.Hour)),
+		newCotacao(pair, "0.0053", baseTime.Add(2*time.Hour)),
+	}
+	if err := saveAll(ctx, repo, cotacoes...); err != nil {
+		return err
+	}
+
+	filtered, err := repo.List(ctx, ListFilter{
+		Pair:      &pair,
+		TimeField: TimeFieldQuote,
+		From:      baseTime.Add(30 * time.Minute),
+		To:        baseTime.Add(2 * time.Hour),
+		Limit:     10,
+	})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(filtered, cotacoes[1].ID); err != nil {
+		return fmt.Errorf("filtro por horário da cotação: %w", err)
+	}
+
+	filtered, err = repo.List(ctx, ListFilter{
+		Pair:      &pair,
+		TimeField: TimeFieldCreatedAt,
+		From:      time.Now().Add(-time.Hour),
+		Limit:     10,
+	})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(filtered, cotacoes[0].ID, cotacoes[1].ID, cotacoes[2].ID); err != nil {
+		return fmt.Errorf("filtro por data de gravação: %w", err)
+	}
+	return nil
+}
+
+func checkCandles(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "CHF"}
+	if err := saveAll(ctx, repo,
+		newCotacao(pair, "2.0", baseTime),
+		newCotacao(pair, "2.5", baseTime.Add(10*time.Minute)),
+		newCotacao(pair, "1.5", baseTime.Add(20*time.Minute)),
+		newCotacao(pair, "1.8", baseTime.Add(30*time.Minute)),
+		newCotacao(pair, "3.0", baseTime.Add(time.Hour)),
+	); err != nil {
+		return err
+	}
+
+	candles, err := repo.Candles(ctx, CandleFilter{
+		Pair:      pair,
+		TimeField: TimeFieldQuote,
+		Interval:  time.Hour,
+	})
+	if err != nil {
+		return err
+	}
+
+	expected := []struct {
+		start                  time.Time
+		open, high, low, close string
+		count                  int64
+	}{
+		{baseTime, "2.0", "2.5", "1.5", "1.8", 4},
+		{baseTime.Add(time.Hour), "3.0", "3.0", "3.0", "3.0", 1},
+	}
+	if len(candles) != len(expected) {
+		return fmt.Errorf("esperados %d candles, obtidos %d", len(expected), len(candles))
+	}
+	for i, want := range expected {
+		got := candles[i]
+		if !got.Start.Equal(want.start) || got.Count != want.count ||
+			got.Open.Cmp(models.MustParseDecimal(want.open)) != 0 ||
+			got.High.Cmp(models.MustParseDecimal(want.high)) != 0 ||
+			got.Low.Cmp(models.MustParseDecimal(want.low)) != 0 ||
+			got.Close.Cmp(models.MustParseDecimal(want.close)) != 0 {
+			return fmt.Errorf("candle %d: esperado %s %s/%s/%s/%s (%d), obtido %s %s/%s/%s/%s (%d)", i,
+				want.start.Format(time.RFC3339), want.open, want.high, want.low, want.close, want.count,
+				got.Start.Format(time.RFC3339), got.Open, got.High, got.Low, got.Close, got.Count)
+		}
+	}
+
+	_, err = repo.Candles(ctx, CandleFilter{Pair: pair, Interval: 0})
+	if err := expectCode(err, "VALIDATION_ERROR"); err != nil {
+		return fmt.Errorf("intervalo inválido: %w", err)
+	}
+	return nil
+}
+
+func checkSaveBatch(ctx context.Context, repo CotacaoRepository) error {
+	saver, ok := repo.(BatchSaver)
+	if !ok {
+		return nil
+	}
+
+	pair := models.Pair{Code: "XTS", Codein: "MXN"}
+	invalid := []*models.Cotacao{newCotacao(pair, "0.3", baseTime), {Bid: models.MustParseDecimal("1")}}
+	if err := expectCode(saver.SaveBatch(ctx, invalid), "VALIDATION_ERROR"); err != nil {
+		return fmt.Errorf("lote inválido: %w", err)
+	}
+
+	batch := []*models.Cotacao{
+		newCotacao(pair, "0.31", baseTime),
+		newCotacao(pair, "0.32", baseTime.Add(time.Minute)),
+		newCotacao(pair, "0.33", baseTime.Add(2*time.Minute)),
+	}
+	if err := saver.SaveBatch(ctx, batch); err != nil {
+		return err
+	}
+	for i := 1; i < len(batch); i++ {
+		if batch[i].ID <= batch[i-1].ID {
+			return fmt.Errorf("ids não crescentes no lote: %d, %d", batch[i-1].ID, batch[i].ID)
+		}
+	}
+
+	listed, err := repo.List(ctx, ListFilter{Pair: &pair, Limit: 10})
+	if err != nil {
+		return err
+	}
+	return expectIDs(listed, batch[0].ID, batch[1].ID, batch[2].ID)
+}
+
+func checkConcurrentSaves(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "AUD"}
+	cotacoes := make([]*models.Cotacao, 20)
+	errs := make([]error, len(cotacoes))
+
+	var wg sync.WaitGroup
+	for i := range cotacoes {
+		cotacoes[i] = newCotacao(pair, "3.0", baseTime.Add(time.Duration(i)*time.Second))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Save(ctx, cotacoes[i])
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, len(cotacoes))
+	for i, cotacao := range cotacoes {
+		if errs[i] != nil {
+			return fmt.Errorf("salvar cotação: %w", errs[i])
+		}
+		if seen[cotacao.ID] {
+			return fmt.Errorf("id %d atribuído mais de uma vez", cotacao.ID)
+		}
+		seen[cotacao.ID] = true
+	}
+
+	listed, err := repo.List(ctx, ListFilter{Pair: &pair, Limit: len(cotacoes) + 1})
+	if err != nil {
+		return err
+	}
+	if len(listed) != len(cotacoes) {
+		return fmt.Errorf("esperadas %d cotações, obtidas %d", len(cotacoes), len(listed))
+	}
+	return nil
+}
+
+func checkExpiredContext(ctx context.Context, repo CotacaoRepository) error {
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+
+	if err := expectCode(repo.Save(expired, newCotacao(models.Pair{Code: "XTS", Codein: "CAD"}, "4.0", baseTime)), "TIMEOUT"); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	_, err := repo.FindLatest(expired, models.DefaultPair)
+	if err := expectCode(err, "TIMEOUT"); err != nil {
+		return fmt.Errorf("find_latest: %w", err)
+	}
+	return nil
+}
+
+func checkIgnoreKeepsFirst(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "PLN"}
+	first := newCotacao(pair, "0.18", baseTime)
+	second := newCotacao(pair, "0.19", baseTime)
+	if err := saveAll(ctx, repo, first, second); err != nil {
+		return err
+	}
+	if second.ID != first.ID {
+		return fmt.Errorf("esperado id %d para a mesma cotação, obtido %d", first.ID, second.ID)
+	}
+
+	found, err := repo.FindByID(ctx, first.ID)
+	if err != nil {
+		return err
+	}
+	if found.Bid.Cmp(first.Bid) != 0 {
+		return fmt.Errorf("esperado bid original %s, obtido %s", first.Bid, found.Bid)
+	}
+
+	listed, err := repo.List(ctx, ListFilter{Pair: &pair, Limit: 10})
+	if err != nil {
+		return err
+	}
+	return expectIDs(listed, first.ID)
+}
+
+func checkUpsertKeepsID(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "NZD"}
+	first := newCotacao(pair, "3.10", baseTime)
+	second := newCotacao(pair, "3.20", baseTime)
+	if err := saveAll(ctx, repo, first, second); err != nil {
+		return err
+	}
+	if second.ID != first.ID {
+		return fmt.Errorf("esperado id %d para a mesma cotação, obtido %d", first.ID, second.ID)
+	}
+
+	found, err := repo.FindByID(ctx, first.ID)
+	if err != nil {
+		return err
+	}
+	if found.Bid.Cmp(second.Bid) != 0 {
+		return fmt.Errorf("esperado bid atualizado %s, obtido %s", second.Bid, found.Bid)
+	}
+
+	listed, err := repo.List(ctx, ListFilter{Pair: &pair, Limit: 10})
+	if err != nil {
+		return err
+	}
+	return expectIDs(listed, first.ID)
+}
+
+func checkDedupDistinctKeys(ctx context.Context, repo CotacaoRepository) error {
+	pair := models.Pair{Code: "XTS", Codein: "SEK"}
+	withoutTimestamp := []*models.Cotacao{newCotacao(pair, "0.5", time.Time{}), newCotacao(pair, "0.5", time.Time{})}
+	cotacoes := []*models.Cotacao{
+		newCotacao(pair, "0.5", baseTime),
+		newCotacao(pair, "0.5", baseTime.Add(time.Second)),
+		newCotacao(models.Pair{Code: "XTS", Codein: "NOK"}, "0.5", baseTime),
+		withoutTimestamp[0],
+		withoutTimestamp[1],
+	}
+	if err := saveAll(ctx, repo, cotacoes...); err != nil {
+		return err
+	}
+
+	seen := make(map[int64]bool, len(cotacoes))
+	for _, cotacao := range cotacoes {
+		if seen[cotacao.ID] {
+			return fmt.Errorf("cotações distintas compartilham o id %d", cotacao.ID)
+		}
+		seen[cotacao.ID] = true
+	}
+	return nil
+}
+
+func checkUpsertBatch(ctx context.Context, repo CotacaoRepository) error {
+	saver, ok := repo.(BatchSaver)
+	if !ok {
+		return nil
+	}
+
+	pair := models.Pair{Code: "XTS", Codein: "DKK"}
+	batch := []*models.Cotacao{
+		newCotacao(pair, "0.80", baseTime),
+		newCotacao(pair, "0.81", baseTime),
+		newCotacao(pair, "0.82", baseTime.Add(time.Minute)),
+	}
+	if err := saver.SaveBatch(ctx, batch); err != nil {
+		return err
+	}
+	if batch[1].ID != batch[0].ID {
+		return fmt.Errorf("esperado id %d para a mesma cotação no lote, obtido %d", batch[0].ID, batch[1].ID)
+	}
+
+	listed, err := repo.List(ctx, ListFilter{Pair: &pair, Limit: 10})
+	if err != nil {
+		return err
+	}
+	if err := expectIDs(listed, batch[0].ID, batch[2].ID); err != nil {
+		return err
+	}
+	if listed[0].Bid.Cmp(batch[1].Bid) != 0 {
+		return fmt.Errorf("esperado bid atualizado %s, obtido %s", batch[1].Bid, listed[0].Bid)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+const dedupIndex = "idx_cotacoes_dedup"
+
+const insertCotacaoSQL = `
+		INSERT INTO cotacoes (code, codein, name, high, low, var_bid, pct_change, bid, ask, "timestamp", create_date, provider, spread,
+			high_places, low_places, var_bid_places, pct_change_places, bid_places, ask_places, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (code, codein, "timestamp") DO NOTHING
+		RETURNING id, created_at, last_seen_at`
+
+const updateCotacaoSQL = `
+		UPDATE cotacoes SET
+			name = ?, high = ?, low = ?, var_bid = ?, pct_change = ?, bid = ?, ask = ?,
+			create_date = ?, provider = ?, spread = ?,
+			high_places = ?, low_places = ?, var_bid_places = ?, pct_change_places = ?, bid_places = ?, ask_places = ?,
+			last_seen_at = CURRENT_TIMESTAMP
+		WHERE code = ? AND codein = ? AND "timestamp" = ?
+		RETURNING id, created_at, last_seen_at`
+
+const touchCotacaoSQL = `
+		UPDATE cotacoes SET last_seen_at = CURRENT_TIMESTAMP
+		WHERE code = ? AND codein = ? AND "timestamp" = ?
+		RETURNING id, created_at, last_seen_at`
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type cotacaoWriter struct {
+	insertSQL string
+	updateSQL string
+	touchSQL  string
+	upsert    bool
+}
+
+func newCotacaoWriter(dialect Dialect, dedup string) cotacaoWriter {
+	return cotacaoWriter{
+		insertSQL: dialect.Rebind(insertCotacaoSQL),
+		updateSQL: dialect.Rebind(updateCotacaoSQL),
+		touchSQL:  dialect.Rebind(touchCotacaoSQL),
+		upsert:    dedup == config.DedupUpsert,
+	}
+}
+
+func (w cotacaoWriter) save(ctx context.Context, q rowQuerier, cotacao *models.Cotacao, args []any) (bool, error) {
+	err := q.QueryRowContext(ctx, w.insertSQL, args...).Scan(&cotacao.ID, &cotacao.CreatedAt, &cotacao.LastSeenAt)
+	if err != sql.ErrNoRows {
+		return err == nil, err
+	}
+
+	key := []any{args[0], args[1], args[9]}
+	if w.upsert {
+		values := append(append(append([]any{}, args[2:9]...), args[10:]...), key...)
+		return false, q.QueryRowContext(ctx, w.updateSQL, values...).Scan(&cotacao.ID, &cotacao.CreatedAt, &cotacao.LastSeenAt)
+	}
+	return false, q.QueryRowContext(ctx, w.touchSQL, key...).Scan(&cotacao.ID, &cotacao.CreatedAt, &cotacao.LastSeenAt)
+}
+
+func requireDedupKey(ctx context.Context, db *sql.DB, query string) error {
+	var count int
+	if err := db.QueryRowContext(ctx, query, dedupIndex).Scan(&count); err != nil {
+		return errors.ErroDatabase(err)
+	}
+	if count == 0 {
+		return errors.ErroIndisponivel("índice " + dedupIndex + " ausente; execute 'dbadmin migrate up'")
+	}
+	return nil
+}
+
+const compactDuplicatesSQL = `
+		DELETE FROM cotacoes
+		WHERE "timestamp" IS NOT NULL
+			AND id NOT IN (
+				SELECT MIN(id)
+				FROM cotacoes
+				WHERE "timestamp" IS NOT NULL
+				GROUP BY code, codein, "timestamp"
+			)`
+
+const countDuplicatesSQL = `
+		SELECT COALESCE(SUM(total - 1), 0)
+		FROM (
+			SELECT COUNT(*) AS total
+			FROM cotacoes
+			WHERE "timestamp" IS NOT NULL
+			GROUP BY code, codein, "timestamp"
+			HAVING COUNT(*) > 1
+		) duplicadas`
+
+func requireNoDuplicates(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+	var duplicates int64
+	if err := tx.QueryRowContext(ctx, countDuplicatesSQL).Scan(&duplicates); err != nil {
+		return err
+	}
+	if duplicates > 0 {
+		return errors.ErroValidacao(fmt.Sprintf(
+			"existem %d cotações duplicadas; execute 'dbadmin compact' antes de criar o índice %s", duplicates, dedupIndex))
+	}
+	return nil
+}
+
+func compactDuplicates(ctx context.Context, db *sql.DB) (int64, error) {
+	result, err := db.ExecContext(ctx, compactDuplicatesSQL)
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			return 0, errors.ErroTimeoutContext("compactar cotações no banco", err)
+		}
+		return 0, errors.ErroDatabase(err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.ErroDatabase(err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+)
+
+type Store interface {
+	CotacaoRepository
+	Close() error
+}
+
+type Migratable interface {
+	Migrator() *Migrator
+}
+
+func NewRepository(cfg config.DatabaseConfig) (Store, error) {
+	if cfg.DSN == MemoryDSN {
+		return NewMemoryRepository(cfg), nil
+	}
+
+	switch cfg.Driver {
+	case "", config.DriverSQLite:
+		repo, err := NewSQLiteRepository(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	case config.DriverPostgres:
+		repo, err := NewPostgresRepository(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return repo, nil
+	default:
+		return nil, errors.ErroValidacao("driver de banco desconhecido: " + cfg.Driver)
+	}
+}
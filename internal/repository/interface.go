@@ -3,11 +3,53 @@ package repository
 import (
 	"client-server-api/pkg/models"
 	"context"
+	"time"
 )
 
 type CotacaoRepository interface {
 	Save(ctx context.Context, cotacao *models.Cotacao) error
 	FindByID(ctx context.Context, id int64) (*models.Cotacao, error)
+	FindLatest(ctx context.Context, pair models.Pair) (*models.Cotacao, error)
+	List(ctx context.Context, filter ListFilter) ([]*models.Cotacao, error)
+	Candles(ctx context.Context, filter CandleFilter) ([]models.Candle, error)
 }
 
+type BatchSaver interface {
+	SaveBatch(ctx context.Context, cotacoes []*models.Cotacao) error
+}
+
+type Inserter interface {
+	Insert(ctx context.Context, cotacao *models.Cotacao) (bool, error)
+	InsertBatch(ctx context.Context, cotacoes []*models.Cotacao) ([]bool, error)
+}
+
+type Compacter interface {
+	Compact(ctx context.Context) (int64, error)
+}
+
+type SchemaChecker interface {
+	CheckSchema(ctx context.Context) error
+}
+
+const (
+	TimeFieldCreatedAt = "created_at"
+	TimeFieldQuote     = "timestamp"
+)
 
+type ListFilter struct {
+	Pair       *models.Pair
+	TimeField  string
+	From       time.Time
+	To         time.Time
+	AfterID    int64
+	Limit      int
+	Descending bool
+}
+
+type CandleFilter struct {
+	Pair      models.Pair
+	TimeField string
+	Interval  time.Duration
+	From      time.Time
+	To        time.Time
+}
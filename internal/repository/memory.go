@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+const MemoryDSN = "memory://"
+
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	cotacoes []models.Cotacao
+	nextID   int64
+	timeout  time.Duration
+
+	upsert bool
+	keys   map[dedupKey]int
+}
+
+type dedupKey struct {
+	pair      models.Pair
+	timestamp int64
+}
+
+func NewMemoryRepository(cfg config.DatabaseConfig) *MemoryRepository {
+	return &MemoryRepository{
+		nextID:  1,
+		timeout: cfg.Timeout,
+		upsert:  cfg.Dedup == config.DedupUpsert,
+		keys:    make(map[dedupKey]int),
+	}
+}
+
+func (r *MemoryRepository) Save(ctx context.Context, cotacao *models.Cotacao) error {
+	_, err := r.Insert(ctx, cotacao)
+	return err
+}
+
+func (r *MemoryRepository) Insert(ctx context.Context, cotacao *models.Cotacao) (bool, error) {
+	if cotacao.Code == "" || cotacao.Codein == "" {
+		return false, errors.ErroValidacao("par da cotação não informado")
+	}
+
+	ctxDB, cancel := r.withTimeout(ctx)
+	defer cancel()
+	if err := ctxDB.Err(); err != nil {
+		return false, memoryError("salvar cotação no banco", err)
+	}
+
+	r.mu.Lock()
+	inserted := r.insert(cotacao)
+	r.mu.Unlock()
+
+	return inserted, nil
+}
+
+func (r *MemoryRepository) SaveBatch(ctx context.Context, cotacoes []*models.Cotacao) error {
+	_, err := r.InsertBatch(ctx, cotacoes)
+	return err
+}
+
+func (r *MemoryRepository) InsertBatch(ctx context.Context, cotacoes []*models.Cotacao) ([]bool, error) {
+	for _, cotacao := range cotacoes {
+		if cotacao.Code == "" || cotacao.Codein == "" {
+			return nil, errors.ErroValidacao("par da cotação não informado")
+		}
+	}
+
+	ctxDB, cancel := r.withTimeout(ctx)
+	defer cancel()
+	if err := ctxDB.Err(); err != nil {
+		return nil, memoryError("salvar lote de cotações no banco", err)
+	}
+
+	inserted := make([]bool, len(cotacoes))
+	r.mu.Lock()
+	for i, cotacao := range cotacoes {
+		inserted[i] = r.insert(cotacao)
+	}
+	r.mu.Unlock()
+
+	return inserted, nil
+}
+
+func (r *MemoryRepository) insert(cotacao *models.Cotacao) bool {
+	stored := *cotacao
+	stored.Timestamp = truncateSecond(stored.Timestamp).UTC()
+	stored.CreateDate = truncateSecond(stored.CreateDate).In(models.SaoPaulo)
+
+	if !stored.Timestamp.IsZero() {
+		key := keyOf(stored)
+		if i, ok := r.keys[key]; ok {
+			stored.ID = r.cotacoes[i].ID
+			stored.CreatedAt = r.cotacoes[i].CreatedAt
+			stored.LastSeenAt = time.Now().UTC().Truncate(time.Second)
+			if r.upsert {
+				r.cotacoes[i] = stored
+			} else {
+				r.cotacoes[i].LastSeenAt = stored.LastSeenAt
+			}
+			cotacao.ID, cotacao.CreatedAt, cotacao.LastSeenAt = stored.ID, stored.CreatedAt, stored.LastSeenAt
+			return false
+		}
+		r.keys[key] = len(r.cotacoes)
+	}
+
+	stored.ID = r.nextID
+	stored.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	stored.LastSeenAt = stored.CreatedAt
+
+	r.nextID++
+	r.cotacoes = append(r.cotacoes, stored)
+	cotacao.ID, cotacao.CreatedAt, cotacao.LastSeenAt = stored.ID, stored.CreatedAt, stored.LastSeenAt
+	return true
+}
+
+func (r *MemoryRepository) FindByID(ctx context.Context, id int64) (*models.Cotacao, error) {
+	ctxDB, cancel := r.withTimeout(ctx)
+	defer cancel()
+	if err := ctxDB.Err(); err != nil {
+		return nil, memoryError("buscar cotação no banco", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := sort.Search(len(r.cotacoes), func(i int) bool { return r.cotacoes[i].ID >= id })
+	if i == len(r.cotacoes) || r.cotacoes[i].ID != id {
+		return nil, errors.ErroNotFound("cotação")
+	}
+
+	cotacao := r.cotacoes[i]
+	return &cotacao, nil
+}
+
+func (r *MemoryRepository) FindLatest(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	ctxDB, cancel := r.withTimeout(ctx)
+	defer cancel()
+	if err := ctxDB.Err(); err != nil {
+		return nil, memoryError("buscar cotação no banco", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.cotacoes) - 1; i >= 0; i-- {
+		if r.cotacoes[i].Code == pair.Code && r.cotacoes[i].Codein == pair.Codein {
+			cotacao := r.cotacoes[i]
+			return &cotacao, nil
+		}
+	}
+
+	return nil, errors.ErroNotFound("cotação")
+}
+
+func (r *MemoryRepository) List(ctx context.Context, filter ListFilter) ([]*models.Cotacao, error) {
+	ctxDB, cancel := r.withTimeout(ctx)
+	defer cancel()
+	if err := ctxDB.Err(); err != nil {
+		return nil, memoryError("listar cotações no banco", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cotacoes := []*models.Cotacao{}
+	matches := func(cotacao models.Cotacao) bool {
+		if filter.Pair != nil && (cotacao.Code != filter.Pair.Code || cotacao.Codein != filter.Pair.Codein) {
+			return false
+		}
+		if filter.AfterID > 0 {
+			if filter.Descending && cotacao.ID >= filter.AfterID {
+				return false
+			}
+			if !filter.Descending && cotacao.ID <= filter.AfterID {
+				return false
+			}
+		}
+		return inRange(timeOf(cotacao, filter.TimeField), filter.From, filter.To)
+	}
+
+	for n := 0; n < len(r.cotacoes) && len(cotacoes) < filter.Limit; n++ {
+		i := n
+		if filter.Descending {
+			i = len(r.cotacoes) - 1 - n
+		}
+		if matches(r.cotacoes[i]) {
+			cotacao := r.cotacoes[i]
+			cotacoes = append(cotacoes, &cotacao)
+		}
+	}
+
+	return cotacoes, nil
+}
+
+func (r *MemoryRepository) Candles(ctx context.Context, filter CandleFilter) ([]models.Candle, error) {
+	ctxDB, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	bucketSeconds := int64(filter.Interval / time.Second)
+	if bucketSeconds <= 0 {
+		return nil, errors.ErroValidacao("intervalo de candle inválido")
+	}
+	if err := ctxDB.Err(); err != nil {
+		return nil, memoryError("agregar cotações no banco", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		candles []models.Candle
+		index   = map[int64]int{}
+	)
+	for _, cotacao := range r.cotacoes {
+		if cotacao.Code != filter.Pair.Code || cotacao.Codein != filter.Pair.Codein {
+			continue
+		}
+		at := timeOf(cotacao, filter.TimeField)
+		if at.IsZero() || !inRange(at, filter.From, filter.To) {
+			continue
+		}
+
+		bucket := (at.Unix() / bucketSeconds) * bucketSeconds
+		i, ok := index[bucket]
+		if !ok {
+			index[bucket] = len(candles)
+			candles = append(candles, models.Candle{
+				Start: time.Unix(bucket, 0).UTC(),
+				Open:  cotacao.Bid,
+				High:  cotacao.Bid,
+				Low:   cotacao.Bid,
+				Close: cotacao.Bid,
+				Count: 1,
+			})
+			continue
+		}
+
+		candle := &candles[i]
+		if cotacao.Bid.Cmp(candle.High) > 0 {
+			candle.High = cotacao.Bid
+		}
+		if cotacao.Bid.Cmp(candle.Low) < 0 {
+			candle.Low = cotacao.Bid
+		}
+		candle.Close = cotacao.Bid
+		candle.Count++
+	}
+
+	sort.Slice(candles, func(i, j int) bool { return candles[i].Start.Before(candles[j].Start) })
+	if candles == nil {
+		candles = []models.Candle{}
+	}
+
+	return candles, nil
+}
+
+func (r *MemoryRepository) Close() error {
+	return nil
+}
+
+func (r *MemoryRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+func keyOf(cotacao models.Cotacao) dedupKey {
+	return dedupKey{
+		pair:      models.Pair{Code: cotacao.Code, Codein: cotacao.Codein},
+		timestamp: cotacao.Timestamp.Unix(),
+	}
+}
+
+func timeOf(cotacao models.Cotacao, field string) time.Time {
+	if field == TimeFieldQuote {
+		return cotacao.Timestamp
+	}
+	return cotacao.CreatedAt
+}
+
+func inRange(value, from, to time.Time) bool {
+	if value.IsZero() {
+		return from.IsZero() && to.IsZero()
+	}
+	if !from.IsZero() && value.Before(truncateSecond(from)) {
+		return false
+	}
+	if !to.IsZero() && !value.Before(truncateSecond(to)) {
+		return false
+	}
+	return true
+}
+
+func truncateSecond(value time.Time) time.Time {
+	if value.IsZero() {
+		return value
+	}
+	return value.Truncate(time.Second)
+}
+
+func memoryError(operation string, err error) error {
+	if err == context.DeadlineExceeded {
+		return errors.ErroTimeoutContext(operation, err)
+	}
+	return errors.ErroDatabase(err)
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/models"
+)
+
+const baselineSchemaSQL = `
+		CREATE TABLE cotacoes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			code TEXT,
+			codein TEXT,
+			name TEXT,
+			high TEXT,
+			low TEXT,
+			var_bid TEXT,
+			pct_change TEXT,
+			bid TEXT,
+			ask TEXT,
+			timestamp TEXT,
+			create_date TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`
+
+var baselineRows = [][]any{
+	{"USD", "BRL", "Dólar Americano/Real Brasileiro", "5.4227", "5.3767", "0.01713", "0.317157", "5.4182", "5.4212", "1765576946", "2025-12-12 19:02:26"},
+	{"USD", "BRL", "Dólar Americano/Real Brasileiro", "5.4310", "5.3801", "-0.0021", "-0.04", "5.4250", "5.4280", "1765577246", "2025-12-12 19:07:26"},
+	{"EUR", "BRL", "Euro/Real Brasileiro", "6.3501", "6.3002", "0.0105", "0.17", "6.3321", "6.3392", "1765577246", "2025-12-12 19:07:26"},
+}
+
+func newBaselineDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "cotacoes.db")
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("abrir banco: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(baselineSchemaSQL); err != nil {
+		t.Fatalf("criar esquema base: %v", err)
+	}
+	for _, row := range baselineRows {
+		_, err := db.Exec(`
+			INSERT INTO cotacoes (code, codein, name, high, low, var_bid, pct_change, bid, ask, timestamp, create_date)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, row...)
+		if err != nil {
+			t.Fatalf("inserir cotação base: %v", err)
+		}
+	}
+
+	return db, dsn
+}
+
+func TestMigratorUpDownUp(t *testing.T) {
+	ctx := context.Background()
+	db, dsn := newBaselineDB(t)
+	migrator := NewMigrator(db, DialectSQLite, migrations)
+
+	up := func(t *testing.T) {
+		t.Helper()
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("Up: %v", err)
+		}
+		expectApplied(t, migrator, len(migrations))
+		expectColumnType(t, db, "bid", "INTEGER")
+		expectColumnType(t, db, "timestamp", "INTEGER")
+		expectColumnType(t, db, "bid_places", "INTEGER")
+		expectIndex(t, db, dedupIndex, true)
+		expectIndex(t, db, "idx_cotacoes_pair_created_at", true)
+		expectMigratedRows(t, dsn)
+	}
+
+	t.Run("up", up)
+
+	t.Run("down", func(t *testing.T) {
+		if _, err := migrator.Down(ctx, len(migrations)-1); err != nil {
+			t.Fatalf("Down: %v", err)
+		}
+		expectApplied(t, migrator, 1)
+		expectColumnType(t, db, "bid", "TEXT")
+		expectColumnType(t, db, "timestamp", "TEXT")
+		expectColumnType(t, db, "bid_places", "")
+		expectIndex(t, db, dedupIndex, false)
+		expectIndex(t, db, "idx_cotacoes_pair_created_at", false)
+		expectBaselineRows(t, db)
+	})
+
+	t.Run("up_again", up)
+}
+
+func TestMigratorRefusesDuplicates(t *testing.T) {
+	ctx := context.Background()
+	db, _ := newBaselineDB(t)
+	_, err := db.Exec(`
+		INSERT INTO cotacoes (code, codein, name, high, low, var_bid, pct_change, bid, ask, timestamp, create_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, baselineRows[0]...)
+	if err != nil {
+		t.Fatalf("inserir cotação duplicada: %v", err)
+	}
+	migrator := NewMigrator(db, DialectSQLite, migrations)
+
+	_, err = migrator.Up(ctx)
+	if err == nil || !strings.Contains(err.Error(), "dbadmin compact") {
+		t.Fatalf("Up = %v, esperado erro pedindo 'dbadmin compact'", err)
+	}
+	expectApplied(t, migrator, 8)
+	expectIndex(t, db, dedupIndex, false)
+
+	removed, err := compactDuplicates(ctx, db)
+	if err != nil {
+		t.Fatalf("compactDuplicates: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removidas = %d, esperado 1", removed)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up após compactar: %v", err)
+	}
+	expectApplied(t, migrator, len(migrations))
+	expectIndex(t, db, dedupIndex, true)
+}
+
+func expectApplied(t *testing.T, migrator *Migrator, want int) {
+	t.Helper()
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	applied := 0
+	for _, status := range statuses {
+		if status.Applied {
+			applied++
+		}
+	}
+	if applied != want {
+		t.Errorf("migrações aplicadas = %d, esperado %d", applied, want)
+	}
+}
+
+func expectColumnType(t *testing.T, db *sql.DB, column, want string) {
+	t.Helper()
+
+	var columnType string
+	err := db.QueryRow(`SELECT type FROM pragma_table_info('cotacoes') WHERE name = ?`, column).Scan(&columnType)
+	if err != nil && err != sql.ErrNoRows {
+		t.Fatalf("consultar coluna %s: %v", column, err)
+	}
+	if !strings.EqualFold(columnType, want) {
+		t.Errorf("tipo da coluna %s = %q, esperado %q", column, columnType, want)
+	}
+}
+
+func expectIndex(t *testing.T, db *sql.DB, name string, want bool) {
+	t.Helper()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?`, name).Scan(&count); err != nil {
+		t.Fatalf("consultar índice %s: %v", name, err)
+	}
+	if (count > 0) != want {
+		t.Errorf("índice %s presente = %t, esperado %t", name, count > 0, want)
+	}
+}
+
+func expectBaselineRows(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	rows, err := db.Query(`SELECT code, codein, bid, timestamp FROM cotacoes ORDER BY id`)
+	if err != nil {
+		t.Fatalf("listar cotações: %v", err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for ; rows.Next(); i++ {
+		var code, codein, bid, timestamp string
+		if err := rows.Scan(&code, &codein, &bid, &timestamp); err != nil {
+			t.Fatalf("ler cotação: %v", err)
+		}
+		if i >= len(baselineRows) {
+			continue
+		}
+		want := baselineRows[i]
+		price, err := models.ParseDecimal(bid)
+		if err != nil {
+			t.Fatalf("cotação %d bid %q: %v", i+1, bid, err)
+		}
+		if code != want[0] || codein != want[1] || price.Cmp(models.MustParseDecimal(want[7].(string))) != 0 || timestamp != want[9] {
+			t.Errorf("cotação %d = %s-%s %s %s, esperado %s-%s %s %s",
+				i+1, code, codein, bid, timestamp, want[0], want[1], want[7], want[9])
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("listar cotações: %v", err)
+	}
+	if i != len(baselineRows) {
+		t.Errorf("cotações = %d, esperado %d", i, len(baselineRows))
+	}
+}
+
+func expectMigratedRows(t *testing.T, dsn string) {
+	t.Helper()
+
+	repo, err := NewSQLiteRepository(config.DatabaseConfig{
+		Driver:         config.DriverSQLite,
+		DSN:            dsn,
+		MaxConnections: 1,
+		Timeout:        time.Second,
+	})
+	if err != nil {
+		t.Fatalf("criar repositório sqlite: %v", err)
+	}
+	defer repo.Close()
+
+	listed, err := repo.List(context.Background(), ListFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(listed) != len(baselineRows) {
+		t.Fatalf("cotações = %d, esperado %d", len(listed), len(baselineRows))
+	}
+
+	for i, cotacao := range listed {
+		want := baselineRows[i]
+		timestamp, err := models.ParseUnixTimestamp(want[9].(string))
+		if err != nil {
+			t.Fatalf("timestamp base: %v", err)
+		}
+		if cotacao.Code != want[0] || cotacao.Codein != want[1] {
+			t.Errorf("cotação %d par = %s-%s, esperado %s-%s", i+1, cotacao.Code, cotacao.Codein, want[0], want[1])
+		}
+		if cotacao.Bid.Cmp(models.MustParseDecimal(want[7].(string))) != 0 {
+			t.Errorf("cotação %d bid = %s, esperado %s", i+1, cotacao.Bid, want[7])
+		}
+		if !cotacao.Timestamp.Equal(timestamp) {
+			t.Errorf("cotação %d timestamp = %s, esperado %s", i+1, cotacao.Timestamp, timestamp)
+		}
+	}
+}
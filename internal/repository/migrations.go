@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_cotacoes",
+		Up: byDialect(
+			execSQL(`
+			CREATE TABLE IF NOT EXISTS cotacoes (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				code TEXT,
+				codein TEXT,
+				name TEXT,
+				high TEXT,
+				low TEXT,
+				var_bid TEXT,
+				pct_change TEXT,
+				bid TEXT,
+				ask TEXT,
+				timestamp TEXT,
+				create_date TEXT,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`),
+			execSQL(`
+			CREATE TABLE IF NOT EXISTS cotacoes (
+				id BIGSERIAL PRIMARY KEY,
+				code TEXT,
+				codein TEXT,
+				name TEXT,
+				high TEXT,
+				low TEXT,
+				var_bid TEXT,
+				pct_change TEXT,
+				bid TEXT,
+				ask TEXT,
+				"timestamp" TEXT,
+				create_date TEXT,
+				created_at TIMESTAMPTZ DEFAULT now()
+			)`),
+		),
+		Down: execSQL(`DROP TABLE cotacoes`),
+	},
+	{
+		Version: 2,
+		Name:    "add_provider_and_spread",
+		Up: byDialect(
+			func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+				if err := sqliteAddColumn(ctx, tx, "cotacoes", "provider", "TEXT"); err != nil {
+					return err
+				}
+				return sqliteAddColumn(ctx, tx, "cotacoes", "spread", "TEXT")
+			},
+			execSQL(`ALTER TABLE cotacoes ADD COLUMN IF NOT EXISTS provider TEXT, ADD COLUMN IF NOT EXISTS spread TEXT`),
+		),
+		Down: execSQL(
+			`ALTER TABLE cotacoes DROP COLUMN spread`,
+			`ALTER TABLE cotacoes DROP COLUMN provider`,
+		),
+	},
+	{
+		Version: 3,
+		Name:    "add_pair_and_created_at_indexes",
+		Up: execSQL(
+			`CREATE INDEX IF NOT EXISTS idx_cotacoes_pair ON cotacoes (code, codein, id)`,
+			`CREATE INDEX IF NOT EXISTS idx_cotacoes_created_at ON cotacoes (created_at)`,
+		),
+		Down: execSQL(
+			`DROP INDEX IF EXISTS idx_cotacoes_created_at`,
+			`DROP INDEX IF EXISTS idx_cotacoes_pair`,
+		),
+	},
+	{
+		Version: 4,
+		Name:    "store_prices_as_decimal_units",
+		Up: byDialect(
+			func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+				return sqliteRebuildCotacoes(ctx, tx, sqliteColumnTypes(priceColumns, "INTEGER"), sqliteConverters(priceColumns, decimalToUnits))
+			},
+			postgresAlterColumns(priceColumns, "BIGINT", "ROUND(NULLIF(%s, '')::numeric * 100000000)::bigint"),
+		),
+		Down: byDialect(
+			func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+				return sqliteRebuildCotacoes(ctx, tx, nil, sqliteConverters(priceColumns, decimalToText))
+			},
+			postgresAlterColumns(priceColumns, "TEXT", "trim_scale(%s::numeric / 100000000)::text"),
+		),
+	},
+	{
+		Version: 5,
+		Name:    "parse_quote_timestamps",
+		Up: byDialect(
+			func(ctx context.Context, tx *sql.Tx, dialect Dialect) error {
+				types := sqliteColumnTypes(priceColumns, "INTEGER")
+				types["timestamp"] = "INTEGER"
+				types["create_date"] = "DATETIME"
+				converters := map[string]func(any) (any, error){
+					"timestamp":   unixTextToInteger,
+					"create_date": createDateToUTC,
+				}
+				if err := sqliteRebuildCotacoes(ctx, tx, types, converters); err != nil {
+					return err
+				}
+				return execSQL(
+					`CREATE INDEX IF NOT EXISTS idx_cotacoes_pair_timestamp ON cotacoes (code, codein, timestamp)`,
+					`CREATE INDEX IF NOT EXISTS idx_cotacoes_create_date ON cotacoes (create_date)`,
+				)(ctx, tx, dialect)
+			},
+			execSQL(
+				`ALTER TABLE cotacoes
+					ALTER COLUMN "timestamp" TYPE BIGINT USING NULLIF("timestamp", '')::bigint,
+					ALTER COLUMN create_date TYPE TIMESTAMPTZ USING NULLIF(create_date, '')::timestamp AT TIME ZONE 'America/Sao_Paulo'`,
+				`CREATE INDEX IF NOT EXISTS idx_cotacoes_pair_timestamp ON cotacoes (code, codein, "timestamp")`,
+				`CREATE INDEX IF NOT EXISTS idx_cotacoes_create_date ON cotacoes (create_date)`,
+			),
+		),
+		Down: byDialect(
+			func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+				converters := map[string]func(any) (any, error){
+					"timestamp":   integerToUnixText,
+					"create_date": utcToCreateDate,
+				}
+				return sqliteRebuildCotacoes(ctx, tx, sqliteColumnTypes(priceColumns, "INTEGER"), converters)
+			},
+			execSQL(
+				`DROP INDEX IF EXISTS idx_cotacoes_create_date`,
+				`DROP INDEX IF EXISTS idx_cotacoes_pair_timestamp`,
+				`ALTER TABLE cotacoes
+					ALTER COLUMN "timestamp" TYPE TEXT USING "timestamp"::text,
+					ALTER COLUMN create_date TYPE TEXT USING to_char(create_date AT TIME ZONE 'America/Sao_Paulo', 'YYYY-MM-DD HH24:MI:SS')`,
+			),
+		),
+	},
+	{
+		Version: 6,
+		Name:    "create_cotacoes_hourly",
+		Up: byDialect(
+			execSQL(`
+			CREATE TABLE IF NOT EXISTS cotacoes_hourly (
+				code TEXT NOT NULL,
+				codein TEXT NOT NULL,
+				bucket INTEGER NOT NULL,
+				open INTEGER,
+				high INTEGER,
+				low INTEGER,
+				close INTEGER,
+				count INTEGER NOT NULL,
+				PRIMARY KEY (code, codein, bucket)
+			)`),
+			execSQL(`
+			CREATE TABLE IF NOT EXISTS cotacoes_hourly (
+				code TEXT NOT NULL,
+				codein TEXT NOT NULL,
+				bucket BIGINT NOT NULL,
+				open BIGINT,
+				high BIGINT,
+				low BIGINT,
+				close BIGINT,
+				count BIGINT NOT NULL,
+				PRIMARY KEY (code, codein, bucket)
+			)`),
+		),
+		Down: execSQL(`DROP TABLE cotacoes_hourly`),
+	},
+	{
+		Version: 7,
+		Name:    "add_price_places",
+		Up: byDialect(
+			func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+				for _, column := range placesColumns() {
+					if err := sqliteAddColumn(ctx, tx, "cotacoes", column, "INTEGER"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			postgresAddColumns(placesColumns(), "SMALLINT"),
+		),
+		Down: func(ctx context.Context, tx *sql.Tx, dialect Dialect) error {
+			for _, column := range placesColumns() {
+				if _, err := tx.ExecContext(ctx, `ALTER TABLE cotacoes DROP COLUMN `+column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 8,
+		Name:    "add_pair_created_at_index",
+		Up:      execSQL(`CREATE INDEX IF NOT EXISTS idx_cotacoes_pair_created_at ON cotacoes (code, codein, created_at)`),
+		Down:    execSQL(`DROP INDEX IF EXISTS idx_cotacoes_pair_created_at`),
+	},
+	{
+		Version: 9,
+		Name:    "add_dedup_unique_index",
+		Up: func(ctx context.Context, tx *sql.Tx, dialect Dialect) error {
+			if err := requireNoDuplicates(ctx, tx, dialect); err != nil {
+				return err
+			}
+			return execSQL(`CREATE UNIQUE INDEX IF NOT EXISTS `+dedupIndex+` ON cotacoes (code, codein, "timestamp")`)(ctx, tx, dialect)
+		},
+		Down: execSQL(`DROP INDEX IF EXISTS ` + dedupIndex),
+	},
+	{
+		Version: 10,
+		Name:    "add_last_seen_at",
+		Up: byDialect(
+			func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+				return sqliteAddColumn(ctx, tx, "cotacoes", "last_seen_at", "DATETIME")
+			},
+			postgresAddColumns([]string{"last_seen_at"}, "TIMESTAMPTZ"),
+		),
+		Down: execSQL(`ALTER TABLE cotacoes DROP COLUMN last_seen_at`),
+	},
+}
+
+func execSQL(statements ...string) MigrationFunc {
+	return func(ctx context.Context, tx *sql.Tx, _ Dialect) error {
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func byDialect(sqlite, postgres MigrationFunc) MigrationFunc {
+	return func(ctx context.Context, tx *sql.Tx, dialect Dialect) error {
+		switch dialect {
+		case DialectSQLite:
+			return sqlite(ctx, tx, dialect)
+		case DialectPostgres:
+			return postgres(ctx, tx, dialect)
+		default:
+			return fmt.Errorf("dialeto não suportado: %s", dialect)
+		}
+	}
+}
+
+func postgresAddColumns(columns []string, columnType string) MigrationFunc {
+	adds := make([]string, len(columns))
+	for i, column := range columns {
+		adds[i] = "ADD COLUMN IF NOT EXISTS " + column + " " + columnType
+	}
+	return execSQL(`ALTER TABLE cotacoes ` + strings.Join(adds, ", "))
+}
+
+func postgresAlterColumns(columns []string, columnType, using string) MigrationFunc {
+	alters := make([]string, len(columns))
+	for i, column := range columns {
+		alters[i] = "ALTER COLUMN " + column + " TYPE " + columnType + " USING " + fmt.Sprintf(using, column)
+	}
+	return execSQL(`ALTER TABLE cotacoes ` + strings.Join(alters, ", "))
+}
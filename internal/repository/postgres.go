@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type PostgresRepository struct {
+	db           *sql.DB
+	timeout      time.Duration
+	batchTimeout time.Duration
+	writer       cotacaoWriter
+}
+
+func NewPostgresRepository(cfg config.DatabaseConfig) (*PostgresRepository, error) {
+	db, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		return nil, errors.ErroDatabase(err)
+	}
+
+	db.SetMaxOpenConns(cfg.MaxConnections)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, errors.ErroDatabase(err)
+	}
+
+	repo := &PostgresRepository{
+		db:           db,
+		timeout:      cfg.Timeout,
+		batchTimeout: max(cfg.WriteBehind.FlushTimeout, cfg.Timeout),
+		writer:       newCotacaoWriter(DialectPostgres, cfg.Dedup),
+	}
+
+	if cfg.AutoMigrate {
+		if _, err := repo.Migrator().Up(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+func (r *PostgresRepository) Save(ctx context.Context, cotacao *models.Cotacao) error {
+	_, err := r.Insert(ctx, cotacao)
+	return err
+}
+
+func (r *PostgresRepository) Insert(ctx context.Context, cotacao *models.Cotacao) (bool, error) {
+	if cotacao.Code == "" || cotacao.Codein == "" {
+		return false, errors.ErroValidacao("par da cotação não informado")
+	}
+
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	inserted, err := r.writer.save(ctxDB, r.db, cotacao, postgresInsertArgs(cotacao))
+	if err != nil {
+		return false, postgresError(ctxDB, "salvar cotação no banco", err)
+	}
+
+	return inserted, nil
+}
+
+func (r *PostgresRepository) SaveBatch(ctx context.Context, cotacoes []*models.Cotacao) error {
+	_, err := r.InsertBatch(ctx, cotacoes)
+	return err
+}
+
+func (r *PostgresRepository) InsertBatch(ctx context.Context, cotacoes []*models.Cotacao) ([]bool, error) {
+	for _, cotacao := range cotacoes {
+		if cotacao.Code == "" || cotacao.Codein == "" {
+			return nil, errors.ErroValidacao("par da cotação não informado")
+		}
+	}
+
+	ctxDB, cancel := context.WithTimeout(ctx, r.batchTimeout)
+	defer cancel()
+
+	ids := make([]int64, len(cotacoes))
+	createdAt := make([]time.Time, len(cotacoes))
+	lastSeenAt := make([]time.Time, len(cotacoes))
+	inserted := make([]bool, len(cotacoes))
+	err := inTx(ctxDB, r.db, func(tx *sql.Tx) error {
+		for i, cotacao := range cotacoes {
+			saved := *cotacao
+			ok, err := r.writer.save(ctxDB, tx, &saved, postgresInsertArgs(cotacao))
+			if err != nil {
+				return err
+			}
+			inserted[i] = ok
+			ids[i], createdAt[i], lastSeenAt[i] = saved.ID, saved.CreatedAt, saved.LastSeenAt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, postgresError(ctxDB, "salvar lote de cotações no banco", err)
+	}
+
+	for i, cotacao := range cotacoes {
+		cotacao.ID = ids[i]
+		cotacao.CreatedAt = createdAt[i]
+		cotacao.LastSeenAt = lastSeenAt[i]
+	}
+
+	return inserted, nil
+}
+
+func (r *PostgresRepository) FindByID(ctx context.Context, id int64) (*models.Cotacao, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	querySQL := `
+		SELECT ` + postgresCotacaoColumns + `
+		FROM cotacoes
+		WHERE id = $1`
+
+	cotacao, err := scanCotacao(r.db.QueryRowContext(ctxDB, querySQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErroNotFound("cotação")
+		}
+		return nil, postgresError(ctxDB, "buscar cotação no banco", err)
+	}
+
+	return cotacao, nil
+}
+
+func (r *PostgresRepository) FindLatest(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	querySQL := `
+		SELECT ` + postgresCotacaoColumns + `
+		FROM cotacoes
+		WHERE code = $1 AND codein = $2
+		ORDER BY id DESC
+		LIMIT 1`
+
+	cotacao, err := scanCotacao(r.db.QueryRowContext(ctxDB, querySQL, pair.Code, pair.Codein))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErroNotFound("cotação")
+		}
+		return nil, postgresError(ctxDB, "buscar cotação no banco", err)
+	}
+
+	return cotacao, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context, filter ListFilter) ([]*models.Cotacao, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	timeColumn := postgresTimeColumn(filter.TimeField)
+
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.Pair != nil {
+		conditions = append(conditions, "code = ? AND codein = ?")
+		args = append(args, filter.Pair.Code, filter.Pair.Codein)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, timeColumn+" >= ?")
+		args = append(args, postgresTimeArg(filter.TimeField, filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, timeColumn+" < ?")
+		args = append(args, postgresTimeArg(filter.TimeField, filter.To))
+	}
+
+	order := "ASC"
+	if filter.Descending {
+		order = "DESC"
+		if filter.AfterID > 0 {
+			conditions = append(conditions, "id < ?")
+			args = append(args, filter.AfterID)
+		}
+	} else if filter.AfterID > 0 {
+		conditions = append(conditions, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+
+	querySQL := `
+		SELECT ` + postgresCotacaoColumns + `
+		FROM cotacoes`
+	if len(conditions) > 0 {
+		querySQL += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+	querySQL += `
+		ORDER BY id ` + order + `
+		LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctxDB, DialectPostgres.Rebind(querySQL), args...)
+	if err != nil {
+		return nil, postgresError(ctxDB, "listar cotações no banco", err)
+	}
+	defer rows.Close()
+
+	cotacoes := []*models.Cotacao{}
+	for rows.Next() {
+		cotacao, err := scanCotacao(rows)
+		if err != nil {
+			return nil, errors.ErroDatabase(err)
+		}
+		cotacoes = append(cotacoes, cotacao)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErroDatabase(err)
+	}
+
+	return cotacoes, nil
+}
+
+func (r *PostgresRepository) Candles(ctx context.Context, filter CandleFilter) ([]models.Candle, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	bucketSeconds := int64(filter.Interval / time.Second)
+	if bucketSeconds <= 0 {
+		return nil, errors.ErroValidacao("intervalo de candle inválido")
+	}
+
+	timeColumn := postgresTimeColumn(filter.TimeField)
+	bucketSource := "FLOOR(EXTRACT(EPOCH FROM created_at))::bigint"
+	if filter.TimeField == TimeFieldQuote {
+		bucketSource = timeColumn
+	}
+
+	conditions := []string{"code = ?", "codein = ?", timeColumn + " IS NOT NULL"}
+	args := []any{bucketSeconds, bucketSeconds, filter.Pair.Code, filter.Pair.Codein}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, timeColumn+" >= ?")
+		args = append(args, postgresTimeArg(filter.TimeField, filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, timeColumn+" < ?")
+		args = append(args, postgresTimeArg(filter.TimeField, filter.To))
+	}
+
+	querySQL := candlesSQL(bucketSource, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.QueryContext(ctxDB, DialectPostgres.Rebind(querySQL), args...)
+	if err != nil {
+		return nil, postgresError(ctxDB, "agregar cotações no banco", err)
+	}
+	defer rows.Close()
+
+	candles := []models.Candle{}
+	for rows.Next() {
+		var (
+			candle models.Candle
+			bucket int64
+		)
+		if err := rows.Scan(&bucket, &candle.Open, &candle.High, &candle.Low, &candle.Close, &candle.Count); err != nil {
+			return nil, errors.ErroDatabase(err)
+		}
+		candle.Start = time.Unix(bucket, 0).UTC()
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErroDatabase(err)
+	}
+
+	return candles, nil
+}
+
+func postgresInsertArgs(cotacao *models.Cotacao) []any {
+	return []any{
+		cotacao.Code,
+		cotacao.Codein,
+		cotacao.Name,
+		cotacao.High,
+		cotacao.Low,
+		cotacao.VarBid,
+		cotacao.PctChange,
+		cotacao.Bid,
+		cotacao.Ask,
+		nullableUnix(cotacao.Timestamp),
+		nullableTime(cotacao.CreateDate),
+		cotacao.Provider,
+		cotacao.Spread,
+		cotacao.High.Places(),
+		cotacao.Low.Places(),
+		cotacao.VarBid.Places(),
+		cotacao.PctChange.Places(),
+		cotacao.Bid.Places(),
+		cotacao.Ask.Places(),
+	}
+}
+
+const postgresCotacaoColumns = `id, code, codein, name, high, low, var_bid, pct_change, bid, ask, "timestamp", create_date,
+			COALESCE(provider, ''), COALESCE(spread, ''), created_at,
+			high_places, low_places, var_bid_places, pct_change_places, bid_places, ask_places, last_seen_at`
+
+func postgresTimeColumn(field string) string {
+	if field == TimeFieldQuote {
+		return `"timestamp"`
+	}
+	return TimeFieldCreatedAt
+}
+
+func postgresTimeArg(field string, value time.Time) any {
+	if field == TimeFieldQuote {
+		return value.Unix()
+	}
+	return value.UTC()
+}
+
+func postgresError(ctx context.Context, operation string, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.ErroTimeoutContext(operation, context.DeadlineExceeded)
+	}
+	return errors.ErroDatabase(err)
+}
+
+func nullableTime(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.UTC()
+}
+
+func (r *PostgresRepository) Compact(ctx context.Context) (int64, error) {
+	return compactDuplicates(ctx, r.db)
+}
+
+func (r *PostgresRepository) CheckSchema(ctx context.Context) error {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return requireDedupKey(ctxDB, r.db, `SELECT COUNT(*) FROM pg_indexes WHERE schemaname = current_schema() AND indexname = $1`)
+}
+
+func (r *PostgresRepository) ApplyRetention(ctx context.Context, cutoff time.Time, dryRun bool) (RetentionResult, error) {
+	bucket := "FLOOR(EXTRACT(EPOCH FROM created_at))::bigint"
+	result, err := applyRetention(ctx, r.db, DialectPostgres, bucket, postgresTimeArg(TimeFieldCreatedAt, cutoff), dryRun)
+	result.Cutoff, result.DryRun = cutoff, dryRun
+	return result, err
+}
+
+func (r *PostgresRepository) Vacuum(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `VACUUM cotacoes`); err != nil {
+		return errors.ErroDatabase(err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *PostgresRepository) Migrator() *Migrator {
+	return NewMigrator(r.db, DialectPostgres, migrations)
+}
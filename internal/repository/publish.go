@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"client-server-api/pkg/models"
+)
+
+type Publisher interface {
+	Publish(cotacao *models.Cotacao)
+}
+
+type PublishingRepository struct {
+	CotacaoRepository
+
+	publisher Publisher
+}
+
+func NewPublishingRepository(repo CotacaoRepository, publisher Publisher) *PublishingRepository {
+	return &PublishingRepository{
+		CotacaoRepository: repo,
+		publisher:         publisher,
+	}
+}
+
+func (r *PublishingRepository) Save(ctx context.Context, cotacao *models.Cotacao) error {
+	inserter, ok := r.CotacaoRepository.(Inserter)
+	if !ok {
+		if err := r.CotacaoRepository.Save(ctx, cotacao); err != nil {
+			return err
+		}
+		r.publisher.Publish(cotacao)
+		return nil
+	}
+
+	inserted, err := inserter.Insert(ctx, cotacao)
+	if err != nil {
+		return err
+	}
+	if inserted {
+		r.publisher.Publish(cotacao)
+	}
+	return nil
+}
+
+func (r *PublishingRepository) SaveBatch(ctx context.Context, cotacoes []*models.Cotacao) error {
+	inserter, ok := r.CotacaoRepository.(Inserter)
+	if !ok {
+		for _, cotacao := range cotacoes {
+			if err := r.Save(ctx, cotacao); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	inserted, err := inserter.InsertBatch(ctx, cotacoes)
+	if err != nil {
+		return err
+	}
+
+	for i, cotacao := range cotacoes {
+		if inserted[i] {
+			r.publisher.Publish(cotacao)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/models"
+)
+
+type recordingPublisher struct {
+	published []int64
+}
+
+func (p *recordingPublisher) Publish(cotacao *models.Cotacao) {
+	p.published = append(p.published, cotacao.ID)
+}
+
+func TestPublishOnlyInsertedCotacoes(t *testing.T) {
+	for _, dedup := range []string{config.DedupIgnore, config.DedupUpsert} {
+		t.Run(dedup, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			repo := NewPublishingRepository(newSQLiteTestRepository(t, dedup), publisher)
+
+			ctx := context.Background()
+			timestamp := time.Unix(1709294400, 0).UTC()
+			newCotacao := func(bid string, timestamp time.Time) *models.Cotacao {
+				return &models.Cotacao{Code: "USD", Codein: "BRL", Bid: models.MustParseDecimal(bid), Timestamp: timestamp}
+			}
+
+			first := newCotacao("5.10", timestamp)
+			if err := repo.Save(ctx, first); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			if err := repo.Save(ctx, newCotacao("5.11", timestamp)); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			batch := []*models.Cotacao{newCotacao("5.12", timestamp), newCotacao("5.13", timestamp.Add(time.Minute))}
+			if err := repo.SaveBatch(ctx, batch); err != nil {
+				t.Fatalf("SaveBatch: %v", err)
+			}
+
+			want := []int64{first.ID, batch[1].ID}
+			if len(publisher.published) != len(want) || publisher.published[0] != want[0] || publisher.published[1] != want[1] {
+				t.Errorf("publicadas = %v, esperado %v", publisher.published, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/models"
+)
+
+func newSQLiteTestRepository(t *testing.T, dedup string) *SQLiteRepository {
+	t.Helper()
+
+	repo, err := NewSQLiteRepository(config.DatabaseConfig{
+		Driver:         config.DriverSQLite,
+		DSN:            filepath.Join(t.TempDir(), "cotacoes.db"),
+		MaxConnections: 1,
+		Timeout:        time.Second,
+		AutoMigrate:    true,
+		Dedup:          dedup,
+	})
+	if err != nil {
+		t.Fatalf("criar repositório sqlite: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	return repo
+}
+
+func TestSaveKeepsPriceScale(t *testing.T) {
+	repos := map[string]CotacaoRepository{
+		"memory": NewMemoryRepository(config.DatabaseConfig{Timeout: time.Second}),
+		"sqlite": newSQLiteTestRepository(t, config.DedupIgnore),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cotacao := &models.Cotacao{
+				Code:      "USD",
+				Codein:    "BRL",
+				High:      models.MustParseDecimal("5.4500"),
+				Low:       models.MustParseDecimal("5.40"),
+				VarBid:    models.MustParseDecimal("-0.0010"),
+				PctChange: models.MustParseDecimal("0.00"),
+				Bid:       models.MustParseDecimal("5.4320"),
+				Ask:       models.MustParseDecimal("5.4330"),
+				Timestamp: time.Unix(1709294400, 0).UTC(),
+			}
+			if err := repo.Save(ctx, cotacao); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			latest, err := repo.FindLatest(ctx, models.Pair{Code: "USD", Codein: "BRL"})
+			if err != nil {
+				t.Fatalf("FindLatest: %v", err)
+			}
+
+			want := map[string][2]models.Decimal{
+				"high":       {cotacao.High, latest.High},
+				"low":        {cotacao.Low, latest.Low},
+				"var_bid":    {cotacao.VarBid, latest.VarBid},
+				"pct_change": {cotacao.PctChange, latest.PctChange},
+				"bid":        {cotacao.Bid, latest.Bid},
+				"ask":        {cotacao.Ask, latest.Ask},
+			}
+			for field, values := range want {
+				if values[0].String() != values[1].String() {
+					t.Errorf("%s = %q, esperado %q", field, values[1].String(), values[0].String())
+				}
+			}
+
+			data, err := latest.Bid.MarshalJSON()
+			if err != nil {
+				t.Fatalf("MarshalJSON: %v", err)
+			}
+			if string(data) != `"5.4320"` {
+				t.Errorf("bid em JSON = %s, esperado \"5.4320\"", data)
+			}
+		})
+	}
+}
+
+func TestSaveSameQuoteRefreshesLastSeen(t *testing.T) {
+	for _, dedup := range []string{config.DedupIgnore, config.DedupUpsert} {
+		t.Run(dedup, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newSQLiteTestRepository(t, dedup)
+			pair := models.Pair{Code: "USD", Codein: "BRL"}
+			quote := func() *models.Cotacao {
+				return &models.Cotacao{
+					Code:      pair.Code,
+					Codein:    pair.Codein,
+					Bid:       models.MustParseDecimal("5.4320"),
+					Timestamp: time.Unix(1709294400, 0).UTC(),
+				}
+			}
+
+			if err := repo.Save(ctx, quote()); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			_, err := repo.db.Exec(`UPDATE cotacoes SET created_at = datetime('now', '-1 hour'), last_seen_at = datetime('now', '-1 hour')`)
+			if err != nil {
+				t.Fatalf("envelhecer cotação: %v", err)
+			}
+
+			again := quote()
+			if err := repo.Save(ctx, again); err != nil {
+				t.Fatalf("Save repetido: %v", err)
+			}
+			if age := time.Since(again.LastSeenAt); age > time.Minute {
+				t.Errorf("last_seen_at retornado com %s de idade, esperado atualizado", age)
+			}
+
+			latest, err := repo.FindLatest(ctx, pair)
+			if err != nil {
+				t.Fatalf("FindLatest: %v", err)
+			}
+			if age := time.Since(latest.CreatedAt); age < 59*time.Minute {
+				t.Errorf("created_at com %s de idade, esperado o da primeira gravação", age)
+			}
+			if age := time.Since(latest.LastSeenAt); age > time.Minute {
+				t.Errorf("last_seen_at com %s de idade, esperado atualizado", age)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"client-server-api/pkg/errors"
+)
+
+type Retainer interface {
+	ApplyRetention(ctx context.Context, cutoff time.Time, dryRun bool) (RetentionResult, error)
+}
+
+type RetentionResult struct {
+	Cutoff      time.Time `json:"cutoff"`
+	Downsampled int64     `json:"downsampled"`
+	Deleted     int64     `json:"deleted"`
+	DryRun      bool      `json:"dry_run"`
+}
+
+func RetentionCutoff(now time.Time, maxAge time.Duration) time.Time {
+	return now.Add(-maxAge).UTC().Truncate(time.Hour)
+}
+
+type Vacuumer interface {
+	Vacuum(ctx context.Context) error
+}
+
+const downsampleSQL = `
+		WITH buckets AS (
+			SELECT
+				code,
+				codein,
+				(%s / 3600) * 3600 AS bucket,
+				MIN(id) AS first_id,
+				MAX(id) AS last_id,
+				MAX(bid) AS high,
+				MIN(bid) AS low,
+				COUNT(*) AS total
+			FROM cotacoes
+			WHERE created_at < ?
+			GROUP BY code, codein, bucket
+		)
+		INSERT INTO cotacoes_hourly (code, codein, bucket, open, high, low, close, count)
+		SELECT b.code, b.codein, b.bucket, o.bid, b.high, b.low, c.bid, b.total
+		FROM buckets b
+		JOIN cotacoes o ON o.id = b.first_id
+		JOIN cotacoes c ON c.id = b.last_id
+		WHERE true
+		ON CONFLICT (code, codein, bucket) DO NOTHING`
+
+const downsampleCountSQL = `
+		SELECT COUNT(*)
+		FROM (
+			SELECT DISTINCT code, codein, (%s / 3600) * 3600 AS bucket
+			FROM cotacoes
+			WHERE created_at < ?
+		) b
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM cotacoes_hourly h
+			WHERE h.code = b.code AND h.codein = b.codein AND h.bucket = b.bucket
+		)`
+
+const expiredCountSQL = `SELECT COUNT(*) FROM cotacoes WHERE created_at < ?`
+
+func applyRetention(
+	ctx context.Context,
+	db *sql.DB,
+	dialect Dialect,
+	bucket string,
+	cutoff any,
+	dryRun bool,
+) (RetentionResult, error) {
+	if dryRun {
+		return countRetention(ctx, db, dialect, bucket, cutoff)
+	}
+
+	var result RetentionResult
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, errors.ErroDatabase(err)
+	}
+	defer tx.Rollback()
+
+	inserted, err := tx.ExecContext(ctx, dialect.Rebind(fmt.Sprintf(downsampleSQL, bucket)), cutoff)
+	if err != nil {
+		return result, retentionError(err)
+	}
+	if result.Downsampled, err = inserted.RowsAffected(); err != nil {
+		return result, errors.ErroDatabase(err)
+	}
+
+	deleted, err := tx.ExecContext(ctx, dialect.Rebind(`DELETE FROM cotacoes WHERE created_at < ?`), cutoff)
+	if err != nil {
+		return result, retentionError(err)
+	}
+	if result.Deleted, err = deleted.RowsAffected(); err != nil {
+		return result, errors.ErroDatabase(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, errors.ErroDatabase(err)
+	}
+
+	return result, nil
+}
+
+func countRetention(ctx context.Context, db *sql.DB, dialect Dialect, bucket string, cutoff any) (RetentionResult, error) {
+	var result RetentionResult
+
+	err := db.QueryRowContext(ctx, dialect.Rebind(fmt.Sprintf(downsampleCountSQL, bucket)), cutoff).Scan(&result.Downsampled)
+	if err != nil {
+		return result, retentionError(err)
+	}
+	if err := db.QueryRowContext(ctx, dialect.Rebind(expiredCountSQL), cutoff).Scan(&result.Deleted); err != nil {
+		return result, retentionError(err)
+	}
+
+	return result, nil
+}
+
+func retentionError(err error) error {
+	if err == context.DeadlineExceeded {
+		return errors.ErroTimeoutContext("aplicar retenção de cotações", err)
+	}
+	return errors.ErroDatabase(err)
+}
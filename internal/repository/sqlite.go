@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -13,8 +14,10 @@ import (
 )
 
 type SQLiteRepository struct {
-	db      *sql.DB
-	timeout time.Duration
+	db           *sql.DB
+	timeout      time.Duration
+	batchTimeout time.Duration
+	writer       cotacaoWriter
 }
 
 func NewSQLiteRepository(cfg config.DatabaseConfig) (*SQLiteRepository, error) {
@@ -35,48 +38,91 @@ func NewSQLiteRepository(cfg config.DatabaseConfig) (*SQLiteRepository, error) {
 	}
 
 	repo := &SQLiteRepository{
-		db:      db,
-		timeout: cfg.Timeout,
+		db:           db,
+		timeout:      cfg.Timeout,
+		batchTimeout: max(cfg.WriteBehind.FlushTimeout, cfg.Timeout),
+		writer:       newCotacaoWriter(DialectSQLite, cfg.Dedup),
 	}
 
-	if err := repo.migrate(); err != nil {
-		db.Close()
-		return nil, errors.ErroDatabase(err)
+	if cfg.AutoMigrate {
+		if _, err := repo.Migrator().Up(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
 	}
 
 	return repo, nil
 }
 
 func (r *SQLiteRepository) Save(ctx context.Context, cotacao *models.Cotacao) error {
+	_, err := r.Insert(ctx, cotacao)
+	return err
+}
+
+func (r *SQLiteRepository) Insert(ctx context.Context, cotacao *models.Cotacao) (bool, error) {
+	if cotacao.Code == "" || cotacao.Codein == "" {
+		return false, errors.ErroValidacao("par da cotação não informado")
+	}
+
 	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	insertSQL := `
-		INSERT INTO cotacoes (code, codein, name, high, low, var_bid, pct_change, bid, ask, timestamp, create_date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	inserted, err := r.writer.save(ctxDB, r.db, cotacao, insertArgs(cotacao))
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			return false, errors.ErroTimeoutContext("salvar cotação no banco", err)
+		}
+		return false, errors.ErroDatabase(err)
+	}
 
-	_, err := r.db.ExecContext(ctxDB, insertSQL,
-		cotacao.Code,
-		cotacao.Codein,
-		cotacao.Name,
-		cotacao.High,
-		cotacao.Low,
-		cotacao.VarBid,
-		cotacao.PctChange,
-		cotacao.Bid,
-		cotacao.Ask,
-		cotacao.Timestamp,
-		cotacao.CreateDate,
-	)
+	return inserted, nil
+}
+
+func (r *SQLiteRepository) SaveBatch(ctx context.Context, cotacoes []*models.Cotacao) error {
+	_, err := r.InsertBatch(ctx, cotacoes)
+	return err
+}
 
+func (r *SQLiteRepository) InsertBatch(ctx context.Context, cotacoes []*models.Cotacao) ([]bool, error) {
+	for _, cotacao := range cotacoes {
+		if cotacao.Code == "" || cotacao.Codein == "" {
+			return nil, errors.ErroValidacao("par da cotação não informado")
+		}
+	}
+
+	ctxDB, cancel := context.WithTimeout(ctx, r.batchTimeout)
+	defer cancel()
+
+	ids := make([]int64, len(cotacoes))
+	createdAt := make([]time.Time, len(cotacoes))
+	lastSeenAt := make([]time.Time, len(cotacoes))
+	inserted := make([]bool, len(cotacoes))
+	err := inTx(ctxDB, r.db, func(tx *sql.Tx) error {
+		for i, cotacao := range cotacoes {
+			saved := *cotacao
+			ok, err := r.writer.save(ctxDB, tx, &saved, insertArgs(cotacao))
+			if err != nil {
+				return err
+			}
+			inserted[i] = ok
+			ids[i], createdAt[i], lastSeenAt[i] = saved.ID, saved.CreatedAt, saved.LastSeenAt
+		}
+		return nil
+	})
 	if err != nil {
 		if err == context.DeadlineExceeded {
-			return errors.ErroTimeoutContext("salvar cotação no banco", err)
+			return nil, errors.ErroTimeoutContext("salvar lote de cotações no banco", err)
 		}
-		return errors.ErroDatabase(err)
+		return nil, errors.ErroDatabase(err)
 	}
 
-	return nil
+	for i, cotacao := range cotacoes {
+		cotacao.ID = ids[i]
+		cotacao.CreatedAt = createdAt[i]
+		cotacao.LastSeenAt = lastSeenAt[i]
+	}
+
+	return inserted, nil
 }
 
 func (r *SQLiteRepository) FindByID(ctx context.Context, id int64) (*models.Cotacao, error) {
@@ -84,12 +130,249 @@ func (r *SQLiteRepository) FindByID(ctx context.Context, id int64) (*models.Cota
 	defer cancel()
 
 	querySQL := `
-		SELECT id, code, codein, name, high, low, var_bid, pct_change, bid, ask, timestamp, create_date, created_at
+		SELECT ` + cotacaoColumns + `
 		FROM cotacoes
 		WHERE id = ?`
 
-	var cotacao models.Cotacao
-	err := r.db.QueryRowContext(ctxDB, querySQL, id).Scan(
+	cotacao, err := scanCotacao(r.db.QueryRowContext(ctxDB, querySQL, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErroNotFound("cotação")
+		}
+		if err == context.DeadlineExceeded {
+			return nil, errors.ErroTimeoutContext("buscar cotação no banco", err)
+		}
+		return nil, errors.ErroDatabase(err)
+	}
+
+	return cotacao, nil
+}
+
+func (r *SQLiteRepository) FindLatest(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	querySQL := `
+		SELECT ` + cotacaoColumns + `
+		FROM cotacoes
+		WHERE code = ? AND codein = ?
+		ORDER BY id DESC
+		LIMIT 1`
+
+	cotacao, err := scanCotacao(r.db.QueryRowContext(ctxDB, querySQL, pair.Code, pair.Codein))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErroNotFound("cotação")
+		}
+		if err == context.DeadlineExceeded {
+			return nil, errors.ErroTimeoutContext("buscar cotação no banco", err)
+		}
+		return nil, errors.ErroDatabase(err)
+	}
+
+	return cotacao, nil
+}
+
+func (r *SQLiteRepository) List(ctx context.Context, filter ListFilter) ([]*models.Cotacao, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if filter.TimeField != TimeFieldQuote {
+		filter.TimeField = TimeFieldCreatedAt
+	}
+
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.Pair != nil {
+		conditions = append(conditions, "code = ? AND codein = ?")
+		args = append(args, filter.Pair.Code, filter.Pair.Codein)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, filter.TimeField+" >= ?")
+		args = append(args, sqliteTimeArg(filter.TimeField, filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, filter.TimeField+" < ?")
+		args = append(args, sqliteTimeArg(filter.TimeField, filter.To))
+	}
+
+	order := "ASC"
+	if filter.Descending {
+		order = "DESC"
+		if filter.AfterID > 0 {
+			conditions = append(conditions, "id < ?")
+			args = append(args, filter.AfterID)
+		}
+	} else if filter.AfterID > 0 {
+		conditions = append(conditions, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+
+	querySQL := `
+		SELECT ` + cotacaoColumns + `
+		FROM cotacoes`
+	if len(conditions) > 0 {
+		querySQL += `
+		WHERE ` + strings.Join(conditions, " AND ")
+	}
+	querySQL += `
+		ORDER BY id ` + order + `
+		LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctxDB, querySQL, args...)
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			return nil, errors.ErroTimeoutContext("listar cotações no banco", err)
+		}
+		return nil, errors.ErroDatabase(err)
+	}
+	defer rows.Close()
+
+	cotacoes := []*models.Cotacao{}
+	for rows.Next() {
+		cotacao, err := scanCotacao(rows)
+		if err != nil {
+			return nil, errors.ErroDatabase(err)
+		}
+		cotacoes = append(cotacoes, cotacao)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErroDatabase(err)
+	}
+
+	return cotacoes, nil
+}
+
+func (r *SQLiteRepository) Candles(ctx context.Context, filter CandleFilter) ([]models.Candle, error) {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	bucketSeconds := int64(filter.Interval / time.Second)
+	if bucketSeconds <= 0 {
+		return nil, errors.ErroValidacao("intervalo de candle inválido")
+	}
+
+	bucketSource := "CAST(strftime('%s', created_at) AS INTEGER)"
+	if filter.TimeField == TimeFieldQuote {
+		bucketSource = "timestamp"
+	} else {
+		filter.TimeField = TimeFieldCreatedAt
+	}
+
+	conditions := []string{"code = ?", "codein = ?", filter.TimeField + " IS NOT NULL"}
+	args := []any{bucketSeconds, bucketSeconds, filter.Pair.Code, filter.Pair.Codein}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, filter.TimeField+" >= ?")
+		args = append(args, sqliteTimeArg(filter.TimeField, filter.From))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, filter.TimeField+" < ?")
+		args = append(args, sqliteTimeArg(filter.TimeField, filter.To))
+	}
+
+	querySQL := candlesSQL(bucketSource, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.QueryContext(ctxDB, querySQL, args...)
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			return nil, errors.ErroTimeoutContext("agregar cotações no banco", err)
+		}
+		return nil, errors.ErroDatabase(err)
+	}
+	defer rows.Close()
+
+	candles := []models.Candle{}
+	for rows.Next() {
+		var (
+			candle models.Candle
+			bucket int64
+		)
+		if err := rows.Scan(&bucket, &candle.Open, &candle.High, &candle.Low, &candle.Close, &candle.Count); err != nil {
+			return nil, errors.ErroDatabase(err)
+		}
+		candle.Start = time.Unix(bucket, 0).UTC()
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.ErroDatabase(err)
+	}
+
+	return candles, nil
+}
+
+func candlesSQL(bucketSource, where string) string {
+	return `
+		WITH filtered AS (
+			SELECT id, bid,
+				(` + bucketSource + ` / ?) * ? AS bucket
+			FROM cotacoes
+			WHERE ` + where + `
+		),
+		buckets AS (
+			SELECT bucket, MIN(id) AS first_id, MAX(id) AS last_id, MAX(bid) AS high, MIN(bid) AS low, COUNT(*) AS total
+			FROM filtered
+			GROUP BY bucket
+		)
+		SELECT b.bucket, o.bid, b.high, b.low, c.bid, b.total
+		FROM buckets b
+		JOIN cotacoes o ON o.id = b.first_id
+		JOIN cotacoes c ON c.id = b.last_id
+		ORDER BY b.bucket`
+}
+
+func insertArgs(cotacao *models.Cotacao) []any {
+	return []any{
+		cotacao.Code,
+		cotacao.Codein,
+		cotacao.Name,
+		cotacao.High,
+		cotacao.Low,
+		cotacao.VarBid,
+		cotacao.PctChange,
+		cotacao.Bid,
+		cotacao.Ask,
+		nullableUnix(cotacao.Timestamp),
+		nullableUTC(cotacao.CreateDate),
+		cotacao.Provider,
+		cotacao.Spread,
+		cotacao.High.Places(),
+		cotacao.Low.Places(),
+		cotacao.VarBid.Places(),
+		cotacao.PctChange.Places(),
+		cotacao.Bid.Places(),
+		cotacao.Ask.Places(),
+	}
+}
+
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+func sqliteTimeArg(field string, value time.Time) any {
+	if field == TimeFieldQuote {
+		return value.Unix()
+	}
+	return value.UTC().Format(sqliteTimeLayout)
+}
+
+const cotacaoColumns = `id, code, codein, name, high, low, var_bid, pct_change, bid, ask, timestamp, create_date,
+			COALESCE(provider, ''), COALESCE(spread, ''), created_at,
+			high_places, low_places, var_bid_places, pct_change_places, bid_places, ask_places, last_seen_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCotacao(row rowScanner) (*models.Cotacao, error) {
+	var (
+		cotacao    models.Cotacao
+		timestamp  sql.NullInt64
+		createDate sql.NullTime
+		places     [6]sql.NullInt64
+		lastSeenAt sql.NullTime
+	)
+	err := row.Scan(
 		&cotacao.ID,
 		&cotacao.Code,
 		&cotacao.Codein,
@@ -100,52 +383,115 @@ func (r *SQLiteRepository) FindByID(ctx context.Context, id int64) (*models.Cota
 		&cotacao.PctChange,
 		&cotacao.Bid,
 		&cotacao.Ask,
-		&cotacao.Timestamp,
-		&cotacao.CreateDate,
+		&timestamp,
+		&createDate,
+		&cotacao.Provider,
+		&cotacao.Spread,
 		&cotacao.CreatedAt,
+		&places[0],
+		&places[1],
+		&places[2],
+		&places[3],
+		&places[4],
+		&places[5],
+		&lastSeenAt,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.ErroNotFound("cotação")
-		}
-		if err == context.DeadlineExceeded {
-			return nil, errors.ErroTimeoutContext("buscar cotação no banco", err)
+		return nil, err
+	}
+
+	prices := []*models.Decimal{&cotacao.High, &cotacao.Low, &cotacao.VarBid, &cotacao.PctChange, &cotacao.Bid, &cotacao.Ask}
+	for i, price := range prices {
+		if places[i].Valid {
+			*price = price.WithPlaces(int(places[i].Int64))
 		}
-		return nil, errors.ErroDatabase(err)
+	}
+
+	if timestamp.Valid {
+		cotacao.Timestamp = time.Unix(timestamp.Int64, 0).UTC()
+	}
+	if createDate.Valid {
+		cotacao.CreateDate = createDate.Time.In(models.SaoPaulo)
+	}
+	cotacao.LastSeenAt = cotacao.CreatedAt
+	if lastSeenAt.Valid {
+		cotacao.LastSeenAt = lastSeenAt.Time
 	}
 
 	return &cotacao, nil
 }
 
-func (r *SQLiteRepository) Close() error {
-	return r.db.Close()
+func nullableUnix(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.Unix()
+}
+
+func nullableUTC(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.UTC().Format(sqliteTimeLayout)
 }
 
-func (r *SQLiteRepository) migrate() error {
-	createTableSQL := `
-		CREATE TABLE IF NOT EXISTS cotacoes (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			code TEXT,
-			codein TEXT,
-			name TEXT,
-			high TEXT,
-			low TEXT,
-			var_bid TEXT,
-			pct_change TEXT,
-			bid TEXT,
-			ask TEXT,
-			timestamp TEXT,
-			create_date TEXT,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-		);`
-
-	_, err := r.db.Exec(createTableSQL)
+func (r *SQLiteRepository) Compact(ctx context.Context) (int64, error) {
+	return compactDuplicates(ctx, r.db)
+}
+
+func (r *SQLiteRepository) CheckSchema(ctx context.Context) error {
+	ctxDB, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return requireDedupKey(ctxDB, r.db, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = ?`)
+}
+
+func (r *SQLiteRepository) ApplyRetention(ctx context.Context, cutoff time.Time, dryRun bool) (RetentionResult, error) {
+	bucket := "CAST(strftime('%s', created_at) AS INTEGER)"
+	result, err := applyRetention(ctx, r.db, DialectSQLite, bucket, sqliteTimeArg(TimeFieldCreatedAt, cutoff), dryRun)
+	result.Cutoff, result.DryRun = cutoff, dryRun
+	if err != nil || dryRun {
+		return result, err
+	}
+
+	return result, r.incrementalVacuum(ctx)
+}
+
+func (r *SQLiteRepository) incrementalVacuum(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, `PRAGMA incremental_vacuum`)
 	if err != nil {
 		return errors.ErroDatabase(err)
 	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		return errors.ErroDatabase(err)
+	}
 
 	return nil
 }
 
+func (r *SQLiteRepository) Vacuum(ctx context.Context) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return errors.ErroDatabase(err)
+	}
+	defer conn.Close()
 
+	if _, err := conn.ExecContext(ctx, `PRAGMA auto_vacuum = INCREMENTAL`); err != nil {
+		return errors.ErroDatabase(err)
+	}
+	if _, err := conn.ExecContext(ctx, `VACUUM`); err != nil {
+		return errors.ErroDatabase(err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRepository) Migrator() *Migrator {
+	return NewMigrator(r.db, DialectSQLite, migrations)
+}
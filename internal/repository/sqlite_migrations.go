@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"client-server-api/pkg/models"
+)
+
+var priceColumns = []string{"high", "low", "var_bid", "pct_change", "bid", "ask"}
+
+func placesColumns() []string {
+	columns := make([]string, len(priceColumns))
+	for i, column := range priceColumns {
+		columns[i] = column + "_places"
+	}
+	return columns
+}
+
+var sqliteCotacoesColumns = []string{
+	"id", "code", "codein", "name", "high", "low", "var_bid", "pct_change", "bid", "ask",
+	"timestamp", "create_date", "created_at", "provider", "spread",
+}
+
+func sqliteColumnTypes(columns []string, columnType string) map[string]string {
+	types := make(map[string]string, len(columns))
+	for _, column := range columns {
+		types[column] = columnType
+	}
+	return types
+}
+
+func sqliteConverters(columns []string, convert func(any) (any, error)) map[string]func(any) (any, error) {
+	converters := make(map[string]func(any) (any, error), len(columns))
+	for _, column := range columns {
+		converters[column] = convert
+	}
+	return converters
+}
+
+func sqliteRebuildCotacoes(
+	ctx context.Context,
+	tx *sql.Tx,
+	types map[string]string,
+	converters map[string]func(any) (any, error),
+) error {
+	definitions := make([]string, 0, len(sqliteCotacoesColumns))
+	for _, column := range sqliteCotacoesColumns {
+		switch column {
+		case "id":
+			definitions = append(definitions, "id INTEGER PRIMARY KEY AUTOINCREMENT")
+		case "created_at":
+			definitions = append(definitions, "created_at DATETIME DEFAULT CURRENT_TIMESTAMP")
+		default:
+			columnType, ok := types[column]
+			if !ok {
+				columnType = "TEXT"
+			}
+			definitions = append(definitions, column+" "+columnType)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "CREATE TABLE cotacoes_new (\n"+strings.Join(definitions, ",\n")+"\n)"); err != nil {
+		return err
+	}
+
+	columns := strings.Join(sqliteCotacoesColumns, ", ")
+	if _, err := tx.ExecContext(ctx, `INSERT INTO cotacoes_new (`+columns+`) SELECT `+columns+` FROM cotacoes`); err != nil {
+		return err
+	}
+
+	converted := make([]string, 0, len(converters))
+	for column := range converters {
+		converted = append(converted, column)
+	}
+	sort.Strings(converted)
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, `+strings.Join(converted, ", ")+` FROM cotacoes`)
+	if err != nil {
+		return err
+	}
+
+	var updates [][]any
+	for rows.Next() {
+		var id int64
+		raw := make([]any, len(converted))
+		dest := []any{&id}
+		for i := range raw {
+			dest = append(dest, &raw[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return err
+		}
+
+		values := make([]any, 0, len(converted)+1)
+		for i, column := range converted {
+			if raw[i] == nil {
+				values = append(values, nil)
+				continue
+			}
+			value, err := converters[column](raw[i])
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("cotação %d, coluna %s: %w", id, column, err)
+			}
+			values = append(values, value)
+		}
+		updates = append(updates, append(values, id))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	updateSQL := `UPDATE cotacoes_new SET ` + strings.Join(converted, " = ?, ") + ` = ? WHERE id = ?`
+	for _, values := range updates {
+		if _, err := tx.ExecContext(ctx, updateSQL, values...); err != nil {
+			return err
+		}
+	}
+
+	return execSQL(
+		`DROP TABLE cotacoes`,
+		`ALTER TABLE cotacoes_new RENAME TO cotacoes`,
+		`CREATE INDEX IF NOT EXISTS idx_cotacoes_pair ON cotacoes (code, codein, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_cotacoes_created_at ON cotacoes (created_at)`,
+	)(ctx, tx, DialectSQLite)
+}
+
+func decimalToUnits(value any) (any, error) {
+	var decimal models.Decimal
+	if err := decimal.Scan(value); err != nil {
+		return nil, err
+	}
+	return decimal.Value()
+}
+
+func decimalToText(value any) (any, error) {
+	var decimal models.Decimal
+	if err := decimal.Scan(value); err != nil {
+		return nil, err
+	}
+	return decimal.String(), nil
+}
+
+func unixTextToInteger(value any) (any, error) {
+	parsed, err := models.ParseUnixTimestamp(asText(value))
+	if err != nil || parsed.IsZero() {
+		return nil, err
+	}
+	return parsed.Unix(), nil
+}
+
+func integerToUnixText(value any) (any, error) {
+	return asText(value), nil
+}
+
+func createDateToUTC(value any) (any, error) {
+	parsed, err := models.ParseCreateDate(asText(value))
+	if err != nil || parsed.IsZero() {
+		return nil, err
+	}
+	return parsed.UTC().Format(sqliteTimeLayout), nil
+}
+
+func utcToCreateDate(value any) (any, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.In(models.SaoPaulo).Format(models.CreateDateLayout), nil
+	default:
+		parsed, err := time.ParseInLocation(sqliteTimeLayout, asText(value), time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		return parsed.In(models.SaoPaulo).Format(models.CreateDateLayout), nil
+	}
+}
+
+func asText(value any) string {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sqliteAddColumn(ctx context.Context, tx *sql.Tx, table, column, definition string) error {
+	var count int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, "ALTER TABLE "+table+" ADD COLUMN "+column+" "+definition)
+	return err
+}
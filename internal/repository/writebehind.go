@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type WriteBehindRepository struct {
+	CotacaoRepository
+
+	queue         chan *models.Cotacao
+	batchSize     int
+	flushInterval time.Duration
+	flushTimeout  time.Duration
+	maxRetries    int
+	retryDelay    time.Duration
+	drop          bool
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+
+	written atomic.Int64
+	dropped atomic.Int64
+	failed  atomic.Int64
+}
+
+type WriteBehindStats struct {
+	Enabled bool  `json:"enabled"`
+	Pending int   `json:"pending"`
+	Written int64 `json:"written"`
+	Dropped int64 `json:"dropped"`
+	Failed  int64 `json:"failed"`
+}
+
+func NewWriteBehindRepository(repo CotacaoRepository, cfg config.WriteBehindConfig) *WriteBehindRepository {
+	w := &WriteBehindRepository{
+		CotacaoRepository: repo,
+		queue:             make(chan *models.Cotacao, cfg.BufferSize),
+		batchSize:         cfg.BatchSize,
+		flushInterval:     cfg.FlushInterval,
+		flushTimeout:      cfg.FlushTimeout,
+		maxRetries:        cfg.MaxRetries,
+		retryDelay:        cfg.RetryDelay,
+		drop:              cfg.FullPolicy == config.WriteBehindDrop,
+		done:              make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Save enfileira a cotação e retorna antes da gravação: ID continua zero e é
+// omitido das respostas até a cotação ser lida de volta do banco.
+func (w *WriteBehindRepository) Save(ctx context.Context, cotacao *models.Cotacao) error {
+	if cotacao.Code == "" || cotacao.Codein == "" {
+		return errors.ErroValidacao("par da cotação não informado")
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.CotacaoRepository.Save(ctx, cotacao)
+	}
+
+	queued := *cotacao
+	if w.drop {
+		select {
+		case w.queue <- &queued:
+		default:
+			w.dropped.Add(1)
+			log.Printf("Fila de gravação cheia, cotação %s descartada\n", models.Pair{Code: cotacao.Code, Codein: cotacao.Codein})
+		}
+		return nil
+	}
+
+	select {
+	case w.queue <- &queued:
+		return nil
+	case <-ctx.Done():
+		return errors.ErroTimeoutContext("enfileirar cotação para gravação", ctx.Err())
+	}
+}
+
+func (w *WriteBehindRepository) Stats() WriteBehindStats {
+	if w == nil {
+		return WriteBehindStats{}
+	}
+
+	return WriteBehindStats{
+		Enabled: true,
+		Pending: len(w.queue),
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+	}
+}
+
+func (w *WriteBehindRepository) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return errors.ErroTimeoutContext("gravar cotações pendentes", ctx.Err())
+	}
+}
+
+func (w *WriteBehindRepository) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.Cotacao, 0, w.batchSize)
+	for {
+		select {
+		case cotacao, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, cotacao)
+			if len(batch) >= w.batchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+func (w *WriteBehindRepository) flush(batch []*models.Cotacao) {
+	delay := w.retryDelay
+	for attempt := 0; len(batch) > 0; attempt++ {
+		saved, err := w.save(batch)
+		w.written.Add(int64(saved))
+		batch = batch[saved:]
+		if err == nil {
+			return
+		}
+
+		if attempt >= w.maxRetries {
+			w.failed.Add(int64(len(batch)))
+			log.Printf("Erro ao gravar lote de %d cotações após %d tentativas, lote descartado: %v\n", len(batch), attempt+1, err)
+			return
+		}
+
+		log.Printf("Erro ao gravar lote de %d cotações, nova tentativa em %s: %v\n", len(batch), delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (w *WriteBehindRepository) save(batch []*models.Cotacao) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.flushTimeout)
+	defer cancel()
+
+	if saver, ok := w.CotacaoRepository.(BatchSaver); ok {
+		if err := saver.SaveBatch(ctx, batch); err != nil {
+			return 0, err
+		}
+		return len(batch), nil
+	}
+
+	for i, cotacao := range batch {
+		if err := w.CotacaoRepository.Save(ctx, cotacao); err != nil {
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
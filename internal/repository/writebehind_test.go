@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/models"
+)
+
+type flakyBatchSaver struct {
+	*MemoryRepository
+	failures int
+	deadline time.Duration
+}
+
+func (f *flakyBatchSaver) SaveBatch(ctx context.Context, cotacoes []*models.Cotacao) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		f.deadline = time.Until(deadline)
+	}
+	if f.failures > 0 {
+		f.failures--
+		return fmt.Errorf("falha simulada")
+	}
+	return f.MemoryRepository.SaveBatch(ctx, cotacoes)
+}
+
+func newWriteBehindTest(repo CotacaoRepository, maxRetries int) *WriteBehindRepository {
+	return NewWriteBehindRepository(repo, config.WriteBehindConfig{
+		BufferSize:    10,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		FlushTimeout:  time.Minute,
+		MaxRetries:    maxRetries,
+		RetryDelay:    time.Millisecond,
+	})
+}
+
+func TestWriteBehindRetriesFailedBatch(t *testing.T) {
+	inner := &flakyBatchSaver{
+		MemoryRepository: NewMemoryRepository(config.DatabaseConfig{Timeout: time.Second}),
+		failures:         2,
+	}
+	w := newWriteBehindTest(inner, 3)
+
+	ctx := context.Background()
+	cotacao := &models.Cotacao{Code: "USD", Codein: "BRL", Bid: models.MustParseDecimal("5.1")}
+	if err := w.Save(ctx, cotacao); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := w.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	stats := w.Stats()
+	if stats.Written != 1 || stats.Failed != 0 {
+		t.Errorf("written = %d, failed = %d, esperado 1 e 0", stats.Written, stats.Failed)
+	}
+	if inner.deadline < time.Second {
+		t.Errorf("prazo do lote = %s, esperado o DB_WRITE_FLUSH_TIMEOUT", inner.deadline)
+	}
+	if _, err := inner.FindLatest(ctx, models.DefaultPair); err != nil {
+		t.Errorf("FindLatest: %v", err)
+	}
+}
+
+func TestWriteBehindDropsAfterRetries(t *testing.T) {
+	inner := &flakyBatchSaver{
+		MemoryRepository: NewMemoryRepository(config.DatabaseConfig{Timeout: time.Second}),
+		failures:         10,
+	}
+	w := newWriteBehindTest(inner, 2)
+
+	ctx := context.Background()
+	for _, bid := range []string{"5.1", "5.2"} {
+		cotacao := &models.Cotacao{Code: "USD", Codein: "BRL", Bid: models.MustParseDecimal(bid)}
+		if err := w.Save(ctx, cotacao); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := w.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	stats := w.Stats()
+	if stats.Written != 0 || stats.Failed != 2 {
+		t.Errorf("written = %d, failed = %d, esperado 0 e 2", stats.Written, stats.Failed)
+	}
+	if inner.failures != 7 {
+		t.Errorf("tentativas = %d, esperado 3", 10-inner.failures)
+	}
+}
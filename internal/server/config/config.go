@@ -1,41 +1,157 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"client-server-api/pkg/models"
 )
 
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	API      APIConfig
+	Cache    CacheConfig
+	Poller   PollerConfig
+	Convert  ConvertConfig
+	Stream   StreamConfig
 }
 
 type ServerConfig struct {
 	Port string
 }
 
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
 type DatabaseConfig struct {
+	Driver         string
 	DSN            string
 	MaxConnections int
 	Timeout        time.Duration
+	AutoMigrate    bool
+	Dedup          string
+
+	WriteBehind WriteBehindConfig
+	Retention   RetentionConfig
+}
+
+type RetentionConfig struct {
+	Enabled  bool
+	MaxAge   time.Duration
+	Interval time.Duration
+	DryRun   bool
+}
+
+const (
+	DedupIgnore = "ignore"
+	DedupUpsert = "upsert"
+)
+
+const (
+	WriteBehindBlock = "block"
+	WriteBehindDrop  = "drop"
+)
+
+type WriteBehindConfig struct {
+	Enabled       bool
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+	FlushTimeout  time.Duration
+	MaxRetries    int
+	RetryDelay    time.Duration
+	FullPolicy    string
+}
+
+type CacheConfig struct {
+	TTL         time.Duration
+	PairTTLs    map[models.Pair]time.Duration
+	StaleMaxAge time.Duration
+}
+
+type StreamConfig struct {
+	Heartbeat   time.Duration
+	BufferSize  int
+	ReplayLimit int
+}
+
+type ConvertConfig struct {
+	Places   int
+	Rounding models.RoundingMode
+}
+
+type PollerConfig struct {
+	Enabled    bool
+	Interval   time.Duration
+	MaxBackoff time.Duration
 }
 
 type APIConfig struct {
-	BaseURL string
-	Timeout time.Duration
+	BaseURL    string
+	Timeout    time.Duration
+	Pairs      []models.Pair
+	Providers  []string
+	ECBURL     string
+	StaticFile string
+
+	Mode                  string
+	ConsensusMaxDeviation float64
+	ConsensusMinProviders int
+
+	Breaker BreakerConfig
+	Retry   RetryConfig
+
+	MaxQuoteAge time.Duration
+}
+
+type RetryConfig struct {
+	MaxAttempts   int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	MaxRetryAfter time.Duration
+}
+
+type BreakerConfig struct {
+	Enabled             bool
+	FailureThreshold    int
+	CoolDown            time.Duration
+	HalfOpenMaxRequests int
 }
 
 func LoadConfig() (*Config, error) {
+	apiConfig, err := loadAPIConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheConfig, err := loadCacheConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	convertConfig, err := loadConvertConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Server:   loadServerConfig(),
 		Database: loadDatabaseConfig(),
-		API:      loadAPIConfig(),
+		API:      apiConfig,
+		Cache:    cacheConfig,
+		Poller:   loadPollerConfig(),
+		Convert:  convertConfig,
+		Stream:   loadStreamConfig(),
 	}
 
 	if config.API.BaseURL == "" {
-		config.API.BaseURL = "https://economia.awesomeapi.com.br/json/last/USD-BRL"
+		config.API.BaseURL = "https://economia.awesomeapi.com.br/json/last"
 	}
 
 	return config, nil
@@ -53,8 +169,13 @@ func loadServerConfig() ServerConfig {
 }
 
 func loadDatabaseConfig() DatabaseConfig {
+	driver := strings.ToLower(os.Getenv("DB_DRIVER"))
+	if driver == "" {
+		driver = DriverSQLite
+	}
+
 	dsn := os.Getenv("DB_DSN")
-	if dsn == "" {
+	if dsn == "" && driver == DriverSQLite {
 		dsn = ".cotacoes.db"
 	}
 
@@ -74,17 +195,126 @@ func loadDatabaseConfig() DatabaseConfig {
 		}
 	}
 
+	autoMigrate := true
+	if parsed, err := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); err == nil {
+		autoMigrate = parsed
+	}
+
+	dedup := strings.ToLower(os.Getenv("DB_DEDUP"))
+	if dedup != DedupUpsert {
+		dedup = DedupIgnore
+	}
+
 	return DatabaseConfig{
+		Driver:         driver,
 		DSN:            dsn,
 		MaxConnections: maxConnections,
 		Timeout:        timeout,
+		AutoMigrate:    autoMigrate,
+		Dedup:          dedup,
+		WriteBehind:    loadWriteBehindConfig(),
+		Retention:      loadRetentionConfig(),
 	}
 }
 
-func loadAPIConfig() APIConfig {
+func loadRetentionConfig() RetentionConfig {
+	enabled := false
+	if parsed, err := strconv.ParseBool(os.Getenv("DB_RETENTION_ENABLED")); err == nil {
+		enabled = parsed
+	}
+
+	days := 90
+	if parsed, err := strconv.Atoi(os.Getenv("DB_RETENTION_DAYS")); err == nil && parsed > 0 {
+		days = parsed
+	}
+
+	intervalStr := os.Getenv("DB_RETENTION_INTERVAL")
+	interval := 24 * time.Hour
+	if intervalStr != "" {
+		if parsed, err := time.ParseDuration(intervalStr); err == nil && parsed > 0 {
+			interval = parsed
+		}
+	}
+
+	dryRun := false
+	if parsed, err := strconv.ParseBool(os.Getenv("DB_RETENTION_DRY_RUN")); err == nil {
+		dryRun = parsed
+	}
+
+	return RetentionConfig{
+		Enabled:  enabled,
+		MaxAge:   time.Duration(days) * 24 * time.Hour,
+		Interval: interval,
+		DryRun:   dryRun,
+	}
+}
+
+func loadWriteBehindConfig() WriteBehindConfig {
+	enabled := false
+	if parsed, err := strconv.ParseBool(os.Getenv("DB_WRITE_BEHIND")); err == nil {
+		enabled = parsed
+	}
+
+	bufferSize := 1000
+	if parsed, err := strconv.Atoi(os.Getenv("DB_WRITE_BUFFER_SIZE")); err == nil && parsed > 0 {
+		bufferSize = parsed
+	}
+
+	batchSize := 100
+	if parsed, err := strconv.Atoi(os.Getenv("DB_WRITE_BATCH_SIZE")); err == nil && parsed > 0 {
+		batchSize = parsed
+	}
+
+	flushIntervalStr := os.Getenv("DB_WRITE_FLUSH_INTERVAL")
+	flushInterval := time.Second
+	if flushIntervalStr != "" {
+		if parsed, err := time.ParseDuration(flushIntervalStr); err == nil && parsed > 0 {
+			flushInterval = parsed
+		}
+	}
+
+	flushTimeoutStr := os.Getenv("DB_WRITE_FLUSH_TIMEOUT")
+	flushTimeout := 5 * time.Second
+	if flushTimeoutStr != "" {
+		if parsed, err := time.ParseDuration(flushTimeoutStr); err == nil && parsed > 0 {
+			flushTimeout = parsed
+		}
+	}
+
+	maxRetries := 3
+	if parsed, err := strconv.Atoi(os.Getenv("DB_WRITE_MAX_RETRIES")); err == nil && parsed >= 0 {
+		maxRetries = parsed
+	}
+
+	retryDelayStr := os.Getenv("DB_WRITE_RETRY_DELAY")
+	retryDelay := 200 * time.Millisecond
+	if retryDelayStr != "" {
+		if parsed, err := time.ParseDuration(retryDelayStr); err == nil && parsed > 0 {
+			retryDelay = parsed
+		}
+	}
+
+	fullPolicy := strings.ToLower(os.Getenv("DB_WRITE_FULL_POLICY"))
+	if fullPolicy != WriteBehindDrop {
+		fullPolicy = WriteBehindBlock
+	}
+
+	return WriteBehindConfig{
+		Enabled:       enabled,
+		BufferSize:    bufferSize,
+		BatchSize:     batchSize,
+		FlushInterval: flushInterval,
+		FlushTimeout:  flushTimeout,
+		MaxRetries:    maxRetries,
+		RetryDelay:    retryDelay,
+		FullPolicy:    fullPolicy,
+	}
+}
+
+func loadAPIConfig() (APIConfig, error) {
 	baseURL := os.Getenv("API_BASE_URL")
 	if baseURL == "" {
-		baseURL = "https://economia.awesomeapi.com.br/json/last/USD-BRL"
+		baseURL = "https://economia.awesomeapi.com.br/json/last"
 	}
 
 	timeoutStr := os.Getenv("API_TIMEOUT")
@@ -95,8 +325,279 @@ func loadAPIConfig() APIConfig {
 		}
 	}
 
+	pairsStr := os.Getenv("API_PAIRS")
+	if pairsStr == "" {
+		pairsStr = "USD-BRL,EUR-BRL,GBP-BRL,JPY-BRL,ARS-BRL"
+	}
+	pairs, err := models.ParsePairs(pairsStr)
+	if err != nil {
+		return APIConfig{}, fmt.Errorf("API_PAIRS inválido: %w", err)
+	}
+	if len(pairs) == 0 {
+		return APIConfig{}, fmt.Errorf("API_PAIRS não pode estar vazio")
+	}
+
+	providersStr := os.Getenv("API_PROVIDERS")
+	if providersStr == "" {
+		providersStr = "awesomeapi"
+	}
+	var providers []string
+	for _, name := range strings.Split(providersStr, ",") {
+		if name = strings.TrimSpace(strings.ToLower(name)); name != "" {
+			providers = append(providers, name)
+		}
+	}
+
+	ecbURL := os.Getenv("API_ECB_URL")
+	if ecbURL == "" {
+		ecbURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	}
+
+	mode := strings.ToLower(os.Getenv("API_MODE"))
+	if mode == "" {
+		mode = "fallback"
+	}
+
+	maxDeviationStr := os.Getenv("API_CONSENSUS_MAX_DEVIATION")
+	maxDeviation := 1.0
+	if maxDeviationStr != "" {
+		if parsed, err := strconv.ParseFloat(maxDeviationStr, 64); err == nil {
+			maxDeviation = parsed
+		}
+	}
+
+	minProvidersStr := os.Getenv("API_CONSENSUS_MIN_PROVIDERS")
+	minProviders := 1
+	if minProvidersStr != "" {
+		if parsed, err := strconv.Atoi(minProvidersStr); err == nil {
+			minProviders = parsed
+		}
+	}
+
+	maxQuoteAgeStr := os.Getenv("API_MAX_QUOTE_AGE")
+	maxQuoteAge := 7 * 24 * time.Hour
+	if maxQuoteAgeStr != "" {
+		if parsed, err := time.ParseDuration(maxQuoteAgeStr); err == nil {
+			maxQuoteAge = parsed
+		}
+	}
+
 	return APIConfig{
-		BaseURL: baseURL,
-		Timeout: timeout,
+		BaseURL:               baseURL,
+		Timeout:               timeout,
+		Pairs:                 pairs,
+		Providers:             providers,
+		ECBURL:                ecbURL,
+		StaticFile:            os.Getenv("API_STATIC_FILE"),
+		Mode:                  mode,
+		ConsensusMaxDeviation: maxDeviation,
+		ConsensusMinProviders: minProviders,
+		Breaker:               loadBreakerConfig(),
+		Retry:                 loadRetryConfig(),
+		MaxQuoteAge:           maxQuoteAge,
+	}, nil
+}
+
+func loadCacheConfig() (CacheConfig, error) {
+	ttlStr := os.Getenv("CACHE_TTL")
+	ttl := time.Duration(0)
+	if ttlStr != "" {
+		if parsed, err := time.ParseDuration(ttlStr); err == nil {
+			ttl = parsed
+		}
+	}
+
+	pairTTLs := make(map[models.Pair]time.Duration)
+	for _, item := range strings.Split(os.Getenv("CACHE_TTL_PAIRS"), ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		pairStr, durationStr, ok := strings.Cut(item, "=")
+		if !ok {
+			return CacheConfig{}, fmt.Errorf("CACHE_TTL_PAIRS inválido: %s", item)
+		}
+		pair, err := models.ParsePair(pairStr)
+		if err != nil {
+			return CacheConfig{}, fmt.Errorf("CACHE_TTL_PAIRS inválido: %w", err)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(durationStr))
+		if err != nil {
+			return CacheConfig{}, fmt.Errorf("CACHE_TTL_PAIRS inválido: %w", err)
+		}
+		pairTTLs[pair] = duration
+	}
+
+	staleMaxAgeStr := os.Getenv("CACHE_STALE_MAX_AGE")
+	staleMaxAge := 5 * time.Minute
+	if staleMaxAgeStr != "" {
+		if parsed, err := time.ParseDuration(staleMaxAgeStr); err == nil {
+			staleMaxAge = parsed
+		}
+	}
+
+	return CacheConfig{
+		TTL:         ttl,
+		PairTTLs:    pairTTLs,
+		StaleMaxAge: staleMaxAge,
+	}, nil
+}
+
+func loadStreamConfig() StreamConfig {
+	heartbeatStr := os.Getenv("STREAM_HEARTBEAT")
+	heartbeat := 15 * time.Second
+	if heartbeatStr != "" {
+		if parsed, err := time.ParseDuration(heartbeatStr); err == nil && parsed > 0 {
+			heartbeat = parsed
+		}
+	}
+
+	bufferSize := 64
+	if parsed, err := strconv.Atoi(os.Getenv("STREAM_BUFFER_SIZE")); err == nil && parsed > 0 {
+		bufferSize = parsed
+	}
+
+	replayLimit := 500
+	if parsed, err := strconv.Atoi(os.Getenv("STREAM_REPLAY_LIMIT")); err == nil && parsed > 0 {
+		replayLimit = parsed
+	}
+
+	return StreamConfig{
+		Heartbeat:   heartbeat,
+		BufferSize:  bufferSize,
+		ReplayLimit: replayLimit,
+	}
+}
+
+func loadConvertConfig() (ConvertConfig, error) {
+	places := 2
+	if placesStr := os.Getenv("CONVERT_PLACES"); placesStr != "" {
+		parsed, err := strconv.Atoi(placesStr)
+		if err != nil || parsed < 0 || parsed > 8 {
+			return ConvertConfig{}, fmt.Errorf("CONVERT_PLACES inválido: %s", placesStr)
+		}
+		places = parsed
+	}
+
+	rounding := models.RoundHalfUp
+	if roundingStr := os.Getenv("CONVERT_ROUNDING"); roundingStr != "" {
+		parsed, err := models.ParseRoundingMode(roundingStr)
+		if err != nil {
+			return ConvertConfig{}, fmt.Errorf("CONVERT_ROUNDING inválido: %w", err)
+		}
+		rounding = parsed
+	}
+
+	return ConvertConfig{
+		Places:   places,
+		Rounding: rounding,
+	}, nil
+}
+
+func loadPollerConfig() PollerConfig {
+	enabled := false
+	if parsed, err := strconv.ParseBool(os.Getenv("POLLER_ENABLED")); err == nil {
+		enabled = parsed
+	}
+
+	intervalStr := os.Getenv("POLLER_INTERVAL")
+	interval := 30 * time.Second
+	if intervalStr != "" {
+		if parsed, err := time.ParseDuration(intervalStr); err == nil && parsed > 0 {
+			interval = parsed
+		}
+	}
+
+	maxBackoffStr := os.Getenv("POLLER_MAX_BACKOFF")
+	maxBackoff := 5 * time.Minute
+	if maxBackoffStr != "" {
+		if parsed, err := time.ParseDuration(maxBackoffStr); err == nil {
+			maxBackoff = parsed
+		}
+	}
+
+	return PollerConfig{
+		Enabled:    enabled,
+		Interval:   interval,
+		MaxBackoff: maxBackoff,
+	}
+}
+
+func loadBreakerConfig() BreakerConfig {
+	enabled := true
+	if parsed, err := strconv.ParseBool(os.Getenv("BREAKER_ENABLED")); err == nil {
+		enabled = parsed
+	}
+
+	thresholdStr := os.Getenv("BREAKER_FAILURE_THRESHOLD")
+	threshold := 5
+	if thresholdStr != "" {
+		if parsed, err := strconv.Atoi(thresholdStr); err == nil {
+			threshold = parsed
+		}
+	}
+
+	coolDownStr := os.Getenv("BREAKER_COOLDOWN")
+	coolDown := 30 * time.Second
+	if coolDownStr != "" {
+		if parsed, err := time.ParseDuration(coolDownStr); err == nil {
+			coolDown = parsed
+		}
+	}
+
+	halfOpenStr := os.Getenv("BREAKER_HALF_OPEN_MAX_REQUESTS")
+	halfOpen := 1
+	if halfOpenStr != "" {
+		if parsed, err := strconv.Atoi(halfOpenStr); err == nil {
+			halfOpen = parsed
+		}
+	}
+
+	return BreakerConfig{
+		Enabled:             enabled,
+		FailureThreshold:    threshold,
+		CoolDown:            coolDown,
+		HalfOpenMaxRequests: halfOpen,
+	}
+}
+
+func loadRetryConfig() RetryConfig {
+	attemptsStr := os.Getenv("API_RETRY_MAX_ATTEMPTS")
+	attempts := 3
+	if attemptsStr != "" {
+		if parsed, err := strconv.Atoi(attemptsStr); err == nil {
+			attempts = parsed
+		}
+	}
+
+	baseDelayStr := os.Getenv("API_RETRY_BASE_DELAY")
+	baseDelay := 20 * time.Millisecond
+	if baseDelayStr != "" {
+		if parsed, err := time.ParseDuration(baseDelayStr); err == nil {
+			baseDelay = parsed
+		}
+	}
+
+	maxDelayStr := os.Getenv("API_RETRY_MAX_DELAY")
+	maxDelay := time.Second
+	if maxDelayStr != "" {
+		if parsed, err := time.ParseDuration(maxDelayStr); err == nil {
+			maxDelay = parsed
+		}
+	}
+
+	maxRetryAfterStr := os.Getenv("API_RETRY_AFTER_MAX")
+	maxRetryAfter := 30 * time.Second
+	if maxRetryAfterStr != "" {
+		if parsed, err := time.ParseDuration(maxRetryAfterStr); err == nil {
+			maxRetryAfter = parsed
+		}
+	}
+
+	return RetryConfig{
+		MaxAttempts:   attempts,
+		BaseDelay:     baseDelay,
+		MaxDelay:      maxDelay,
+		MaxRetryAfter: maxRetryAfter,
 	}
 }
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"hash/fnv"
+	"io"
+	"strconv"
+	"time"
+
+	"client-server-api/internal/server/render"
+	"client-server-api/pkg/models"
+)
+
+func quoteCache(cotacao *models.Cotacao, maxAge time.Duration) render.Cache {
+	h := fnv.New64a()
+	writeQuoteTag(h, cotacao)
+
+	return render.Cache{
+		Tag:          strconv.FormatUint(h.Sum64(), 36),
+		LastModified: quoteTime(cotacao),
+		MaxAge:       maxAge,
+	}
+}
+
+func historicoCache(response models.HistoricoResponse) render.Cache {
+	h := fnv.New64a()
+	var lastModified time.Time
+	for _, cotacao := range response.Items {
+		h.Write([]byte(strconv.FormatInt(cotacao.ID, 10) + ":"))
+		writeQuoteTag(h, cotacao)
+		if at := quoteTime(cotacao); at.After(lastModified) {
+			lastModified = at
+		}
+	}
+	h.Write([]byte(response.NextCursor))
+
+	return render.Cache{
+		Tag:          strconv.FormatUint(h.Sum64(), 36),
+		LastModified: lastModified,
+	}
+}
+
+func writeQuoteTag(h io.Writer, cotacao *models.Cotacao) {
+	h.Write([]byte(models.Pair{Code: cotacao.Code, Codein: cotacao.Codein}.String() + "|" +
+		strconv.FormatInt(cotacao.Timestamp.Unix(), 10) + "|" + cotacao.Bid.String() + ";"))
+}
+
+func quoteTime(cotacao *models.Cotacao) time.Time {
+	if !cotacao.Timestamp.IsZero() {
+		return cotacao.Timestamp
+	}
+	return cotacao.CreatedAt
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"client-server-api/internal/repository"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+var candleIntervals = map[string]time.Duration{
+	"1m": time.Minute,
+	"5m": 5 * time.Minute,
+	"1h": time.Hour,
+	"1d": 24 * time.Hour,
+}
+
+func (h *CotacaoHandler) GetCandles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	pair := models.DefaultPair
+	if value := query.Get("pair"); value != "" {
+		parsed, err := models.ParsePair(value)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		pair = parsed
+	}
+
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+	duration, ok := candleIntervals[interval]
+	if !ok {
+		h.handleError(w, errors.ErroValidacao("interval deve ser 1m, 5m, 1h ou 1d"))
+		return
+	}
+
+	timeField, err := parseTimeField(query.Get("time"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	from, err := parseTimeParam(query.Get("from"), "from")
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"), "to")
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		h.handleError(w, errors.ErroValidacao("from deve ser anterior a to"))
+		return
+	}
+
+	candles, err := h.service.GetCandles(r.Context(), repository.CandleFilter{
+		Pair:      pair,
+		TimeField: timeField,
+		Interval:  duration,
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, models.CandlesResponse{
+		Pair:     pair.String(),
+		Interval: interval,
+		Candles:  candles,
+	})
+}
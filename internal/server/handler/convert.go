@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"client-server-api/internal/server/service"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type ConversionHandler struct {
+	service *service.ConversionService
+}
+
+func NewConversionHandler(service *service.ConversionService) *ConversionHandler {
+	return &ConversionHandler{
+		service: service,
+	}
+}
+
+func (h *ConversionHandler) Convert(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	amount := models.MustParseDecimal("1")
+	if value := query.Get("amount"); value != "" {
+		parsed, err := models.ParseDecimal(value)
+		if err != nil {
+			handleError(w, errors.ErroValidacao("valor inválido: "+value))
+			return
+		}
+		amount = parsed
+	}
+
+	conversion, err := h.service.Convert(r.Context(), query.Get("from"), query.Get("to"), amount)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	response := models.ConversionResponse{
+		From:   conversion.From,
+		To:     conversion.To,
+		Amount: conversion.Amount,
+		Result: conversion.Result,
+		Rate:   conversion.Rate,
+		Quotes: make([]models.ConversionQuote, len(conversion.Legs)),
+	}
+	for i, leg := range conversion.Legs {
+		price := leg.Cotacao.Bid
+		if leg.Side == service.SideAsk {
+			price = leg.Cotacao.Ask
+		}
+
+		response.Quotes[i] = models.ConversionQuote{
+			ID:        leg.Cotacao.ID,
+			Pair:      models.Pair{Code: leg.Cotacao.Code, Codein: leg.Cotacao.Codein}.String(),
+			Side:      leg.Side,
+			Price:     price,
+			Timestamp: leg.Cotacao.Timestamp,
+		}
+		if leg.Stale {
+			response.Stale = true
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
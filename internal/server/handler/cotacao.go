@@ -3,7 +3,10 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"client-server-api/internal/server/render"
 	"client-server-api/internal/server/service"
 	"client-server-api/pkg/errors"
 	"client-server-api/pkg/models"
@@ -11,26 +14,135 @@ import (
 
 type CotacaoHandler struct {
 	service *service.CotacaoService
+	pairs   []models.Pair
 }
 
-func NewCotacaoHandler(service *service.CotacaoService) *CotacaoHandler {
+func NewCotacaoHandler(service *service.CotacaoService, pairs []models.Pair) *CotacaoHandler {
 	return &CotacaoHandler{
 		service: service,
+		pairs:   pairs,
 	}
 }
 
 func (h *CotacaoHandler) GetCotacao(w http.ResponseWriter, r *http.Request) {
-	bid, err := h.service.GetBid(r.Context())
+	fields, err := parseFields(r.URL.Query().Get("fields"))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	response := models.BidResponse{Bid: bid}
+	h.writeCotacao(w, r, fields)
+}
+
+func (h *CotacaoHandler) GetCotacaoFull(w http.ResponseWriter, r *http.Request) {
+	fields, err := parseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	if fields.minimal() {
+		fields.full = true
+	}
+
+	h.writeCotacao(w, r, fields)
+}
+
+func (h *CotacaoHandler) writeCotacao(w http.ResponseWriter, r *http.Request, fields fieldSelection) {
+	pair, err := h.resolvePair(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	result, err := h.service.GetCotacao(r.Context(), pair)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	cotacao := result.Cotacao
+
+	if result.Stale {
+		w.Header().Set("X-Cache", "STALE")
+	} else if result.Cached {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+	w.Header().Set("Age", strconv.Itoa(int(result.Age.Seconds())))
+	if result.Stale {
+		w.Header().Set("X-Stale", "true")
+	}
+
+	cache := quoteCache(cotacao, h.service.CacheTTL(pair))
+	if result.Stale {
+		cache.MaxAge = 0
+	}
+
+	if !fields.minimal() {
+		response := models.CotacaoResponse{Cotacao: cotacao}
+		if result.Stale {
+			response.Stale = true
+			response.Age = int64(result.Age.Seconds())
+		}
+
+		selected, err := fields.apply(response)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		render.WriteCached(w, r, http.StatusOK, selected, cache)
+		return
+	}
+
+	response := models.BidResponse{Bid: cotacao.Bid.String()}
+	if cotacao.Spread != "" {
+		response.Providers = strings.Split(cotacao.Provider, ",")
+		response.Spread = cotacao.Spread
+	}
+	if result.Stale {
+		response.Stale = true
+		response.Age = int64(result.Age.Seconds())
+	}
+
+	render.WriteCached(w, r, http.StatusOK, response, cache)
+}
+
+func (h *CotacaoHandler) ListPares(w http.ResponseWriter, r *http.Request) {
+	response := models.ParesResponse{Pairs: make([]string, len(h.pairs))}
+	for i, pair := range h.pairs {
+		response.Pairs[i] = pair.String()
+	}
 
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+func (h *CotacaoHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.service.CacheStats())
+}
+
+func (h *CotacaoHandler) resolvePair(r *http.Request) (models.Pair, error) {
+	value := r.PathValue("pair")
+	if value == "" {
+		value = r.URL.Query().Get("pair")
+	}
+	if value == "" {
+		return models.DefaultPair, nil
+	}
+
+	pair, err := models.ParsePair(value)
+	if err != nil {
+		return models.Pair{}, err
+	}
+
+	for _, configured := range h.pairs {
+		if configured == pair {
+			return pair, nil
+		}
+	}
+
+	return models.Pair{}, errors.ErroValidacao("par não suportado: " + pair.String())
+}
+
 func (h *CotacaoHandler) handleError(w http.ResponseWriter, err error) {
 	var appErr *errors.AppError
 	if !errors.As(err, &appErr) {
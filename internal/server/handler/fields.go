@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+var cotacaoFields = []string{
+	"id", "code", "codein", "name", "high", "low", "var_bid", "pct_change", "bid", "ask",
+	"timestamp", "create_date", "provider", "spread", "created_at", "stale", "age",
+}
+
+type fieldSelection struct {
+	full  bool
+	names []string
+}
+
+func parseFields(value string) (fieldSelection, error) {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "":
+		return fieldSelection{}, nil
+	case "all", "*":
+		return fieldSelection{full: true}, nil
+	}
+
+	var selection fieldSelection
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isCotacaoField(name) {
+			return fieldSelection{}, errors.ErroValidacao("campo desconhecido: " + name)
+		}
+		selection.names = append(selection.names, name)
+	}
+	if len(selection.names) == 0 {
+		return fieldSelection{}, errors.ErroValidacao("parâmetro fields inválido: " + value)
+	}
+
+	return selection, nil
+}
+
+func (s fieldSelection) minimal() bool {
+	return !s.full && len(s.names) == 0
+}
+
+func (s fieldSelection) apply(response models.CotacaoResponse) (any, error) {
+	if s.full {
+		return response, nil
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, errors.ErroInterno(err)
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, errors.ErroInterno(err)
+	}
+
+	selected := selectedFields{values: make(map[string]json.RawMessage, len(s.names))}
+	for _, name := range s.names {
+		if _, seen := selected.values[name]; seen {
+			continue
+		}
+		if value, ok := all[name]; ok {
+			selected.names = append(selected.names, name)
+			selected.values[name] = value
+		}
+	}
+	return selected, nil
+}
+
+type selectedFields struct {
+	names  []string
+	values map[string]json.RawMessage
+}
+
+func (s selectedFields) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.values)
+}
+
+func (s selectedFields) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "cotacao"
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, name := range s.names {
+		if err := e.EncodeElement(s.text(name), xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (s selectedFields) CSVRecords() [][]string {
+	row := make([]string, len(s.names))
+	for i, name := range s.names {
+		row[i] = s.text(name)
+	}
+	return [][]string{s.names, row}
+}
+
+func (s selectedFields) PlainText() string {
+	var b strings.Builder
+	for _, name := range s.names {
+		b.WriteString(name + ": " + s.text(name) + "\n")
+	}
+	return b.String()
+}
+
+func (s selectedFields) text(name string) string {
+	var value string
+	if err := json.Unmarshal(s.values[name], &value); err == nil {
+		return value
+	}
+	return string(s.values[name])
+}
+
+func isCotacaoField(name string) bool {
+	for _, field := range cotacaoFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/render"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+const (
+	defaultHistoricoLimit = 50
+	maxHistoricoLimit     = 500
+)
+
+func (h *CotacaoHandler) ListCotacoes(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseListFilter(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+
+	cotacoes, err := h.service.ListCotacoes(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response := models.HistoricoResponse{Items: cotacoes}
+	if len(cotacoes) > limit {
+		response.Items = cotacoes[:limit]
+		response.NextCursor = encodeCursor(response.Items[limit-1].ID)
+		w.Header().Set("X-Next-Cursor", response.NextCursor)
+	}
+
+	render.WriteCached(w, r, http.StatusOK, response, historicoCache(response))
+}
+
+func (h *CotacaoHandler) GetCotacaoByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.handleError(w, errors.ErroValidacao("id inválido: "+r.PathValue("id")))
+		return
+	}
+
+	cotacao, err := h.service.GetCotacaoByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	render.WriteCached(w, r, http.StatusOK, cotacao, quoteCache(cotacao, 0))
+}
+
+func parseListFilter(r *http.Request) (repository.ListFilter, error) {
+	query := r.URL.Query()
+	filter := repository.ListFilter{
+		Limit:      defaultHistoricoLimit,
+		Descending: true,
+	}
+
+	if value := query.Get("pair"); value != "" {
+		pair, err := models.ParsePair(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Pair = &pair
+	}
+
+	var err error
+	if filter.TimeField, err = parseTimeField(query.Get("time")); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseTimeParam(query.Get("from"), "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeParam(query.Get("to"), "to"); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.ErroValidacao("from deve ser anterior a to")
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxHistoricoLimit {
+			return filter, errors.ErroValidacao("limit deve estar entre 1 e " + strconv.Itoa(maxHistoricoLimit))
+		}
+		filter.Limit = limit
+	}
+
+	switch query.Get("sort") {
+	case "", "-created_at":
+		filter.Descending = true
+	case "created_at":
+		filter.Descending = false
+	default:
+		return filter, errors.ErroValidacao("sort inválido: " + query.Get("sort"))
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		id, err := decodeCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.AfterID = id
+	}
+
+	return filter, nil
+}
+
+func parseTimeField(value string) (string, error) {
+	switch value {
+	case "", "created":
+		return repository.TimeFieldCreatedAt, nil
+	case "quote":
+		return repository.TimeFieldQuote, nil
+	default:
+		return "", errors.ErroValidacao("time deve ser created ou quote")
+	}
+}
+
+func parseTimeParam(value, name string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, nil
+	}
+
+	return time.Time{}, errors.ErroValidacao(name + " deve estar no formato RFC 3339 ou AAAA-MM-DD")
+}
+
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(value string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return 0, errors.ErroValidacao("cursor inválido")
+	}
+
+	id, err := strconv.ParseInt(string(decoded), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.ErroValidacao("cursor inválido")
+	}
+
+	return id, nil
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"client-server-api/pkg/errors"
+)
+
+func handleError(w http.ResponseWriter, err error) {
+	var appErr *errors.AppError
+	if !errors.As(err, &appErr) {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error": "Internal server error",
+		})
+		return
+	}
+
+	writeJSON(w, errors.GetHTTPStatus(appErr), map[string]string{
+		"error": appErr.Message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+	}
+}
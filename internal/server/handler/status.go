@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"client-server-api/internal/external"
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/poller"
+)
+
+type StatusHandler struct {
+	poller   *poller.Poller
+	breakers []*external.CircuitBreaker
+	writer   *repository.WriteBehindRepository
+}
+
+func NewStatusHandler(poller *poller.Poller, breakers []*external.CircuitBreaker, writer *repository.WriteBehindRepository) *StatusHandler {
+	return &StatusHandler{
+		poller:   poller,
+		breakers: breakers,
+		writer:   writer,
+	}
+}
+
+func (h *StatusHandler) GetPollerStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.poller.Status())
+}
+
+func (h *StatusHandler) GetCircuitStatus(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]external.BreakerStatus, len(h.breakers))
+	for i, breaker := range h.breakers {
+		statuses[i] = breaker.Status()
+	}
+
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (h *StatusHandler) GetWriterStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.writer.Stats())
+}
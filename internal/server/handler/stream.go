@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+	"client-server-api/internal/server/service"
+	"client-server-api/internal/server/stream"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+const (
+	streamPageSize   = 100
+	streamRetryDelay = time.Second
+)
+
+type StreamHandler struct {
+	broker      *stream.Broker
+	service     *service.CotacaoService
+	pairs       []models.Pair
+	heartbeat   time.Duration
+	replayLimit int
+}
+
+func NewStreamHandler(broker *stream.Broker, service *service.CotacaoService, pairs []models.Pair, cfg config.StreamConfig) *StreamHandler {
+	return &StreamHandler{
+		broker:      broker,
+		service:     service,
+		pairs:       pairs,
+		heartbeat:   cfg.Heartbeat,
+		replayLimit: cfg.ReplayLimit,
+	}
+}
+
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	pairs, err := h.resolvePairs(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	lastID, err := parseLastEventID(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		handleError(w, errors.ErroInterno(fmt.Errorf("streaming não suportado")))
+		return
+	}
+
+	sub, err := h.broker.Subscribe(pairs)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	defer h.broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetryDelay.Milliseconds()); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	if lastID > 0 {
+		replayed, complete, err := h.replay(r.Context(), w, pairs, lastID)
+		if err != nil {
+			log.Printf("Erro ao reenviar cotações a partir de %d: %v\n", lastID, err)
+			return
+		}
+		flusher.Flush()
+		if !complete {
+			return
+		}
+		lastID = replayed
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case cotacao, ok := <-sub.Events:
+			if !ok {
+				if sub.Overflowed() {
+					fmt.Fprint(w, ": fila do cliente excedida, reconecte com Last-Event-ID\n\n")
+					flusher.Flush()
+				}
+				return
+			}
+			if cotacao.ID <= lastID {
+				continue
+			}
+			if err := writeEvent(w, cotacao); err != nil {
+				return
+			}
+			lastID = cotacao.ID
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *StreamHandler) replay(ctx context.Context, w io.Writer, pairs []models.Pair, afterID int64) (int64, bool, error) {
+	wanted := make(map[models.Pair]bool, len(pairs))
+	for _, pair := range pairs {
+		wanted[pair] = true
+	}
+
+	filter := repository.ListFilter{
+		TimeField: repository.TimeFieldCreatedAt,
+		AfterID:   afterID,
+		Limit:     streamPageSize,
+	}
+	if len(pairs) == 1 {
+		filter.Pair = &pairs[0]
+	}
+
+	sent := 0
+	for {
+		cotacoes, err := h.service.ListCotacoes(ctx, filter)
+		if err != nil {
+			return filter.AfterID, false, err
+		}
+
+		for _, cotacao := range cotacoes {
+			filter.AfterID = cotacao.ID
+			if !wanted[models.Pair{Code: cotacao.Code, Codein: cotacao.Codein}] {
+				continue
+			}
+			if err := writeEvent(w, cotacao); err != nil {
+				return filter.AfterID, false, err
+			}
+			if sent++; sent >= h.replayLimit {
+				return filter.AfterID, false, nil
+			}
+		}
+
+		if len(cotacoes) < streamPageSize {
+			return filter.AfterID, true, nil
+		}
+	}
+}
+
+func writeEvent(w io.Writer, cotacao *models.Cotacao) error {
+	data, err := json.Marshal(cotacao)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: cotacao\ndata: %s\n\n", cotacao.ID, data)
+	return err
+}
+
+func (h *StreamHandler) resolvePairs(r *http.Request) ([]models.Pair, error) {
+	value := r.URL.Query().Get("pairs")
+	if value == "" {
+		value = r.URL.Query().Get("pair")
+	}
+	if strings.TrimSpace(value) == "" {
+		return []models.Pair{models.DefaultPair}, nil
+	}
+
+	pairs, err := models.ParsePairs(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, errors.ErroValidacao("parâmetro pairs inválido: " + value)
+	}
+
+	for _, pair := range pairs {
+		supported := false
+		for _, configured := range h.pairs {
+			if configured == pair {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, errors.ErroValidacao("par não suportado: " + pair.String())
+		}
+	}
+
+	return pairs, nil
+}
+
+func parseLastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.ErroValidacao("Last-Event-ID inválido: " + value)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,168 @@
+package poller
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"client-server-api/internal/external"
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/models"
+)
+
+type Status struct {
+	Running             bool      `json:"running"`
+	Pairs               []string  `json:"pairs"`
+	LastSuccess         time.Time `json:"last_success,omitzero"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastErrorAt         time.Time `json:"last_error_at,omitzero"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	NextPoll            time.Time `json:"next_poll,omitzero"`
+}
+
+type Poller struct {
+	apiClient  external.ExchangeRateClient
+	repository repository.CotacaoRepository
+	pairs      []models.Pair
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	mu     sync.Mutex
+	status Status
+	done   chan struct{}
+}
+
+func NewPoller(
+	apiClient external.ExchangeRateClient,
+	repo repository.CotacaoRepository,
+	pairs []models.Pair,
+	cfg config.PollerConfig,
+) *Poller {
+	names := make([]string, len(pairs))
+	for i, pair := range pairs {
+		names[i] = pair.String()
+	}
+
+	return &Poller{
+		apiClient:  apiClient,
+		repository: repo,
+		pairs:      pairs,
+		interval:   cfg.Interval,
+		maxBackoff: cfg.MaxBackoff,
+		status:     Status{Pairs: names},
+		done:       make(chan struct{}),
+	}
+}
+
+func (p *Poller) Start(ctx context.Context) {
+	p.mu.Lock()
+	p.status.Running = true
+	p.mu.Unlock()
+
+	go p.run(ctx)
+}
+
+func (p *Poller) Wait() {
+	<-p.done
+}
+
+func (p *Poller) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := p.status
+	status.Pairs = append([]string(nil), p.status.Pairs...)
+	return status
+}
+
+func (p *Poller) run(ctx context.Context) {
+	defer close(p.done)
+	defer func() {
+		p.mu.Lock()
+		p.status.Running = false
+		p.status.NextPoll = time.Time{}
+		p.mu.Unlock()
+	}()
+
+	for {
+		delay := p.pollOnce(ctx)
+
+		p.mu.Lock()
+		p.status.NextPoll = time.Now().Add(delay)
+		p.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (p *Poller) pollOnce(ctx context.Context) time.Duration {
+	ctxPoll, cancel := context.WithTimeout(ctx, p.interval)
+	defer cancel()
+
+	err := p.poll(ctxPoll)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		p.status.LastSuccess = time.Now()
+		p.status.ConsecutiveFailures = 0
+		return p.interval
+	}
+
+	if ctx.Err() != nil {
+		return p.interval
+	}
+
+	p.status.LastError = err.Error()
+	p.status.LastErrorAt = time.Now()
+	p.status.ConsecutiveFailures++
+
+	delay := p.interval
+	for i := 0; i < p.status.ConsecutiveFailures && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, max(p.maxBackoff, p.interval))
+
+	log.Printf("Erro ao atualizar cotações (%d falhas seguidas, próxima tentativa em %s): %v\n",
+		p.status.ConsecutiveFailures, delay, err)
+
+	return delay
+}
+
+func (p *Poller) poll(ctx context.Context) error {
+	if batch, ok := p.apiClient.(external.BatchExchangeRateClient); ok {
+		cotacoes, err := batch.FetchBatch(ctx, p.pairs)
+		if err != nil {
+			return err
+		}
+
+		for _, cotacao := range cotacoes {
+			if err := p.repository.Save(ctx, cotacao); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var lastErr error
+	for _, pair := range p.pairs {
+		cotacao, err := p.apiClient.Fetch(ctx, pair)
+		if err == nil {
+			err = p.repository.Save(ctx, cotacao)
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
@@ -0,0 +1,72 @@
+package render
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Cache struct {
+	Tag          string
+	LastModified time.Time
+	MaxAge       time.Duration
+}
+
+var tagSuffixes = map[string]string{
+	JSON: "json",
+	XML:  "xml",
+	CSV:  "csv",
+	Text: "txt",
+}
+
+func (c Cache) etag(mediaType string) string {
+	return `W/"` + c.Tag + "-" + tagSuffixes[mediaType] + `"`
+}
+
+func (c Cache) setHeaders(w http.ResponseWriter, mediaType string) {
+	if c.Tag != "" {
+		w.Header().Set("ETag", c.etag(mediaType))
+	}
+	if !c.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", c.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if c.MaxAge > 0 {
+		w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(int(c.MaxAge.Seconds())))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+}
+
+func (c Cache) notModified(r *http.Request, mediaType string) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if c.Tag == "" {
+			return false
+		}
+		return matchETag(inm, c.etag(mediaType))
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !c.LastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !c.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+func matchETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
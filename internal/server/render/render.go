@@ -0,0 +1,187 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	JSON = "application/json"
+	XML  = "application/xml"
+	CSV  = "text/csv"
+	Text = "text/plain"
+)
+
+var contentTypes = map[string]string{
+	JSON: "application/json",
+	XML:  "application/xml; charset=utf-8",
+	CSV:  "text/csv; charset=utf-8",
+	Text: "text/plain; charset=utf-8",
+}
+
+type CSVMarshaler interface {
+	CSVRecords() [][]string
+}
+
+type TextMarshaler interface {
+	PlainText() string
+}
+
+func Offers(data any) []string {
+	offers := []string{JSON, XML}
+	if _, ok := data.(CSVMarshaler); ok {
+		offers = append(offers, CSV)
+	}
+	if _, ok := data.(TextMarshaler); ok {
+		offers = append(offers, Text)
+	}
+	return offers
+}
+
+func Write(w http.ResponseWriter, r *http.Request, status int, data any) {
+	write(w, r, status, data, nil)
+}
+
+func WriteCached(w http.ResponseWriter, r *http.Request, status int, data any, cache Cache) {
+	write(w, r, status, data, &cache)
+}
+
+func write(w http.ResponseWriter, r *http.Request, status int, data any, cache *Cache) {
+	w.Header().Add("Vary", "Accept")
+
+	offers := Offers(data)
+	mediaType, ok := Negotiate(r.Header.Get("Accept"), offers)
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "nenhuma representação aceitável; disponíveis: "+strings.Join(offers, ", "))
+		return
+	}
+
+	if cache != nil {
+		cache.setHeaders(w, mediaType)
+		if cache.notModified(r, mediaType) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	body, err := encode(mediaType, data)
+	if err != nil {
+		for _, name := range []string{"ETag", "Last-Modified", "Cache-Control"} {
+			w.Header().Del(name)
+		}
+		writeError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypes[mediaType])
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func encode(mediaType string, data any) ([]byte, error) {
+	switch mediaType {
+	case XML:
+		body, err := xml.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), append(body, '\n')...), nil
+	case CSV:
+		var buf bytes.Buffer
+		if err := csv.NewWriter(&buf).WriteAll(data.(CSVMarshaler).CSVRecords()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Text:
+		return []byte(data.(TextMarshaler).PlainText()), nil
+	default:
+		body, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		return append(body, '\n'), nil
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", contentTypes[JSON])
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": message}); err != nil {
+	}
+}
+
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+func Negotiate(accept string, offers []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := quality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best, bestQ > 0
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.ToLower(name) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 && parsed <= 1 {
+				q = parsed
+			}
+		}
+
+		ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return specificity(ranges[i]) > specificity(ranges[j]) })
+	return ranges
+}
+
+func quality(ranges []mediaRange, offer string) float64 {
+	typ, subtype, _ := strings.Cut(offer, "/")
+	for _, mr := range ranges {
+		if (mr.typ == "*" || mr.typ == typ) && (mr.subtype == "*" || mr.subtype == subtype) {
+			return mr.q
+		}
+	}
+	return 0
+}
+
+func specificity(mr mediaRange) int {
+	switch {
+	case mr.typ == "*":
+		return 0
+	case mr.subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,76 @@
+package retention
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+)
+
+type Scheduler struct {
+	repository repository.Retainer
+	maxAge     time.Duration
+	interval   time.Duration
+	dryRun     bool
+
+	done chan struct{}
+}
+
+func NewScheduler(repo repository.Retainer, cfg config.RetentionConfig) *Scheduler {
+	return &Scheduler{
+		repository: repo,
+		maxAge:     cfg.MaxAge,
+		interval:   cfg.Interval,
+		dryRun:     cfg.DryRun,
+		done:       make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *Scheduler) Wait() {
+	<-s.done
+}
+
+func (s *Scheduler) RunOnce(ctx context.Context) (repository.RetentionResult, error) {
+	cutoff := repository.RetentionCutoff(time.Now(), s.maxAge)
+	return s.repository.ApplyRetention(ctx, cutoff, s.dryRun)
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Println("Erro ao aplicar retenção de cotações:", err)
+		} else {
+			logResult(result)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func logResult(result repository.RetentionResult) {
+	prefix := "Retenção"
+	if result.DryRun {
+		prefix = "Retenção (simulação)"
+	}
+	log.Printf("%s: %d cotações anteriores a %s removidas, %d agregados por hora criados\n",
+		prefix, result.Deleted, result.Cutoff.Format(time.RFC3339), result.Downsampled)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/models"
+)
+
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+type QuoteCache struct {
+	mu         sync.Mutex
+	defaultTTL time.Duration
+	pairTTLs   map[models.Pair]time.Duration
+	entries    map[models.Pair]cacheEntry
+	hits       uint64
+	misses     uint64
+}
+
+type cacheEntry struct {
+	cotacao  models.Cotacao
+	storedAt time.Time
+}
+
+func NewQuoteCache(cfg config.CacheConfig) *QuoteCache {
+	return &QuoteCache{
+		defaultTTL: cfg.TTL,
+		pairTTLs:   cfg.PairTTLs,
+		entries:    make(map[models.Pair]cacheEntry),
+	}
+}
+
+func (c *QuoteCache) TTL(pair models.Pair) time.Duration {
+	if ttl, ok := c.pairTTLs[pair]; ok {
+		return ttl
+	}
+
+	return c.defaultTTL
+}
+
+func (c *QuoteCache) Get(pair models.Pair) (*models.Cotacao, time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[pair]
+	if ok {
+		age := time.Since(entry.storedAt)
+		if age < c.TTL(pair) {
+			c.hits++
+			cotacao := entry.cotacao
+			return &cotacao, age, true
+		}
+		delete(c.entries, pair)
+	}
+
+	c.misses++
+	return nil, 0, false
+}
+
+func (c *QuoteCache) Set(pair models.Pair, cotacao *models.Cotacao) {
+	if c.TTL(pair) <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[pair] = cacheEntry{
+		cotacao:  *cotacao,
+		storedAt: time.Now(),
+	}
+}
+
+func (c *QuoteCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.entries),
+	}
+}
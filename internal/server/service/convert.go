@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+const (
+	SideBid = "bid"
+	SideAsk = "ask"
+)
+
+const conversionPivot = "BRL"
+
+const ratePlaces = 8
+
+type ConversionService struct {
+	cotacoes *CotacaoService
+	pairs    []models.Pair
+	places   int
+	rounding models.RoundingMode
+}
+
+type Conversion struct {
+	From   string
+	To     string
+	Amount models.Decimal
+	Result models.Decimal
+	Rate   models.Decimal
+	Legs   []ConversionLeg
+}
+
+type ConversionLeg struct {
+	Cotacao *models.Cotacao
+	Side    string
+	Stale   bool
+}
+
+func NewConversionService(cotacoes *CotacaoService, pairs []models.Pair, cfg config.ConvertConfig) *ConversionService {
+	return &ConversionService{
+		cotacoes: cotacoes,
+		pairs:    pairs,
+		places:   cfg.Places,
+		rounding: cfg.Rounding,
+	}
+}
+
+func (s *ConversionService) Convert(ctx context.Context, from, to string, amount models.Decimal) (*Conversion, error) {
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	if !models.IsCurrency(from) {
+		return nil, errors.ErroValidacao("moeda de origem inválida: " + from)
+	}
+	if !models.IsCurrency(to) {
+		return nil, errors.ErroValidacao("moeda de destino inválida: " + to)
+	}
+	if from == to {
+		return nil, errors.ErroValidacao("moedas de origem e destino iguais: " + from)
+	}
+	if amount.Sign() <= 0 {
+		return nil, errors.ErroValidacao("valor deve ser maior que zero")
+	}
+
+	route, ok := s.route(from, to)
+	if !ok {
+		return nil, errors.ErroValidacao("conversão não suportada: " + from + "-" + to)
+	}
+
+	rate := big.NewRat(1, 1)
+	legs := make([]ConversionLeg, 0, len(route))
+	for _, step := range route {
+		result, err := s.cotacoes.GetCotacao(ctx, step.pair)
+		if err != nil {
+			return nil, err
+		}
+		cotacao := result.Cotacao
+
+		if step.inverse {
+			if cotacao.Ask.Sign() <= 0 {
+				return nil, errors.ErroAPI(fmt.Errorf("cotação de venda inválida para %s", step.pair))
+			}
+			rate.Quo(rate, cotacao.Ask.Rat())
+			legs = append(legs, ConversionLeg{Cotacao: cotacao, Side: SideAsk, Stale: result.Stale})
+			continue
+		}
+
+		rate.Mul(rate, cotacao.Bid.Rat())
+		legs = append(legs, ConversionLeg{Cotacao: cotacao, Side: SideBid, Stale: result.Stale})
+	}
+
+	converted, err := models.RoundDecimal(new(big.Rat).Mul(amount.Rat(), rate), s.places, s.rounding)
+	if err != nil {
+		return nil, errors.ErroValidacao("valor convertido fora do intervalo suportado")
+	}
+	roundedRate, err := models.RoundDecimal(rate, ratePlaces, models.RoundHalfUp)
+	if err != nil {
+		return nil, errors.ErroValidacao("taxa de conversão fora do intervalo suportado")
+	}
+
+	return &Conversion{
+		From:   from,
+		To:     to,
+		Amount: amount,
+		Result: converted,
+		Rate:   roundedRate.Trim(),
+		Legs:   legs,
+	}, nil
+}
+
+type conversionStep struct {
+	pair    models.Pair
+	inverse bool
+}
+
+func (s *ConversionService) route(from, to string) ([]conversionStep, bool) {
+	if step, ok := s.step(from, to); ok {
+		return []conversionStep{step}, true
+	}
+	if from == conversionPivot || to == conversionPivot {
+		return nil, false
+	}
+
+	first, ok := s.step(from, conversionPivot)
+	if !ok {
+		return nil, false
+	}
+	second, ok := s.step(conversionPivot, to)
+	if !ok {
+		return nil, false
+	}
+
+	return []conversionStep{first, second}, true
+}
+
+func (s *ConversionService) step(from, to string) (conversionStep, bool) {
+	for _, pair := range s.pairs {
+		if pair.Code == from && pair.Codein == to {
+			return conversionStep{pair: pair}, true
+		}
+	}
+	for _, pair := range s.pairs {
+		if pair.Code == to && pair.Codein == from {
+			return conversionStep{pair: pair, inverse: true}, true
+		}
+	}
+	return conversionStep{}, false
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type stubRateClient map[models.Pair]models.Cotacao
+
+func (c stubRateClient) Fetch(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	cotacao, ok := c[pair]
+	if !ok {
+		return nil, errors.ErroAPI(fmt.Errorf("par não configurado: %s", pair))
+	}
+	return &cotacao, nil
+}
+
+func newConversionTestService(t *testing.T) *ConversionService {
+	t.Helper()
+
+	usd := models.Pair{Code: "USD", Codein: "BRL"}
+	eur := models.Pair{Code: "EUR", Codein: "BRL"}
+	client := stubRateClient{
+		usd: {Code: "USD", Codein: "BRL", Bid: models.MustParseDecimal("5.00"), Ask: models.MustParseDecimal("5.10")},
+		eur: {Code: "EUR", Codein: "BRL", Bid: models.MustParseDecimal("6.00"), Ask: models.MustParseDecimal("6.20")},
+	}
+	repo := repository.NewMemoryRepository(config.DatabaseConfig{Timeout: time.Second})
+	cotacoes := NewCotacaoService(client, repo, config.CacheConfig{TTL: time.Minute}, config.PollerConfig{})
+
+	return NewConversionService(cotacoes, []models.Pair{usd, eur}, config.ConvertConfig{
+		Places:   2,
+		Rounding: models.RoundHalfUp,
+	})
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name       string
+		from, to   string
+		amount     string
+		wantResult string
+		wantRate   string
+		wantSides  []string
+	}{
+		{"direct", "USD", "BRL", "10", "50.00", "5", []string{SideBid}},
+		{"inverse", "BRL", "USD", "51", "10.00", "0.19607843", []string{SideAsk}},
+		{"cross_via_brl", "USD", "EUR", "10", "8.06", "0.80645161", []string{SideBid, SideAsk}},
+		{"cross_reverse", "eur", "usd", "10", "11.76", "1.17647059", []string{SideBid, SideAsk}},
+	}
+	service := newConversionTestService(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversion, err := service.Convert(context.Background(), tt.from, tt.to, models.MustParseDecimal(tt.amount))
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if conversion.Result.String() != tt.wantResult {
+				t.Errorf("resultado = %s, esperado %s", conversion.Result, tt.wantResult)
+			}
+			if conversion.Rate.String() != tt.wantRate {
+				t.Errorf("taxa = %s, esperado %s", conversion.Rate, tt.wantRate)
+			}
+			if len(conversion.Legs) != len(tt.wantSides) {
+				t.Fatalf("pernas = %d, esperado %d", len(conversion.Legs), len(tt.wantSides))
+			}
+			for i, leg := range conversion.Legs {
+				if leg.Side != tt.wantSides[i] {
+					t.Errorf("perna %d lado = %s, esperado %s", i, leg.Side, tt.wantSides[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConvertRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		amount   string
+	}{
+		{"same_currency", "USD", "USD", "10"},
+		{"unknown_currency", "XYZ", "BRL", "10"},
+		{"no_route", "USD", "GBP", "10"},
+		{"zero_amount", "USD", "BRL", "0"},
+		{"negative_amount", "USD", "BRL", "-1"},
+	}
+	service := newConversionTestService(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Convert(context.Background(), tt.from, tt.to, models.MustParseDecimal(tt.amount))
+			var appErr *errors.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+				t.Errorf("Convert = %v, esperado erro de validação", err)
+			}
+		})
+	}
+}
+
+func TestConversionRoute(t *testing.T) {
+	service := newConversionTestService(t)
+	tests := []struct {
+		from, to string
+		want     []conversionStep
+	}{
+		{"USD", "BRL", []conversionStep{{pair: models.Pair{Code: "USD", Codein: "BRL"}}}},
+		{"BRL", "EUR", []conversionStep{{pair: models.Pair{Code: "EUR", Codein: "BRL"}, inverse: true}}},
+		{"EUR", "USD", []conversionStep{
+			{pair: models.Pair{Code: "EUR", Codein: "BRL"}},
+			{pair: models.Pair{Code: "USD", Codein: "BRL"}, inverse: true},
+		}},
+	}
+	for _, tt := range tests {
+		got, ok := service.route(tt.from, tt.to)
+		if !ok {
+			t.Errorf("route(%s, %s) sem rota", tt.from, tt.to)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("route(%s, %s) = %v, esperado %v", tt.from, tt.to, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("route(%s, %s)[%d] = %v, esperado %v", tt.from, tt.to, i, got[i], tt.want[i])
+			}
+		}
+	}
+
+	if got, ok := service.route("USD", "GBP"); ok {
+		t.Errorf("route(USD, GBP) = %v, esperado sem rota", got)
+	}
+}
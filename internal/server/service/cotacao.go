@@ -2,37 +2,155 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"time"
 
 	"client-server-api/internal/external"
 	"client-server-api/internal/repository"
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
 )
 
 type CotacaoService struct {
-	apiClient  external.ExchangeRateClient
-	repository repository.CotacaoRepository
+	apiClient    external.ExchangeRateClient
+	repository   repository.CotacaoRepository
+	cache        *QuoteCache
+	flight       *flightGroup
+	staleMaxAge  time.Duration
+	readStored   bool
+	pollInterval time.Duration
+}
+
+type CotacaoResult struct {
+	Cotacao *models.Cotacao
+	Cached  bool
+	Stale   bool
+	Age     time.Duration
 }
 
 func NewCotacaoService(
 	apiClient external.ExchangeRateClient,
 	repo repository.CotacaoRepository,
+	cfg config.CacheConfig,
+	pollerCfg config.PollerConfig,
 ) *CotacaoService {
 	return &CotacaoService{
-		apiClient:  apiClient,
-		repository: repo,
+		apiClient:    apiClient,
+		repository:   repo,
+		cache:        NewQuoteCache(cfg),
+		flight:       newFlightGroup(),
+		staleMaxAge:  cfg.StaleMaxAge,
+		readStored:   pollerCfg.Enabled,
+		pollInterval: pollerCfg.Interval,
+	}
+}
+
+func (s *CotacaoService) GetCotacao(ctx context.Context, pair models.Pair) (*CotacaoResult, error) {
+	if s.readStored {
+		cotacao, err := s.repository.FindLatest(ctx, pair)
+		if err == nil {
+			return s.storedResult(pair, cotacao)
+		}
+
+		var appErr *errors.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+			return nil, err
+		}
+	}
+
+	if cotacao, age, ok := s.cache.Get(pair); ok {
+		return &CotacaoResult{Cotacao: cotacao, Cached: true, Age: age}, nil
+	}
+
+	cotacao, err := s.flight.Do(ctx, pair, func(ctx context.Context) (*models.Cotacao, error) {
+		return s.fetchAndSave(ctx, pair)
+	})
+	if err != nil {
+		if stale, ok := s.findStale(ctx, pair, err); ok {
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	return &CotacaoResult{Cotacao: cotacao}, nil
+}
+
+func (s *CotacaoService) storedResult(pair models.Pair, cotacao *models.Cotacao) (*CotacaoResult, error) {
+	age := time.Since(lastSeen(cotacao))
+	if age <= 2*s.pollInterval {
+		return &CotacaoResult{Cotacao: cotacao, Cached: true, Age: age}, nil
+	}
+	if age > s.staleMaxAge {
+		return nil, errors.ErroIndisponivel(fmt.Sprintf("cotação armazenada de %s desatualizada há %s", pair, age.Round(time.Second)))
+	}
+
+	return &CotacaoResult{Cotacao: cotacao, Cached: true, Stale: true, Age: age}, nil
+}
+
+func lastSeen(cotacao *models.Cotacao) time.Time {
+	if !cotacao.LastSeenAt.IsZero() {
+		return cotacao.LastSeenAt
 	}
+	return cotacao.CreatedAt
 }
 
-func (s *CotacaoService) GetBid(ctx context.Context) (string, error) {
-	cotacao, err := s.apiClient.FetchUSD(ctx)
+func (s *CotacaoService) findStale(ctx context.Context, pair models.Pair, cause error) (*CotacaoResult, bool) {
+	var appErr *errors.AppError
+	if s.staleMaxAge <= 0 || ctx.Err() != nil || !errors.As(cause, &appErr) {
+		return nil, false
+	}
+	if appErr.Code != "TIMEOUT" && appErr.Code != "API_ERROR" && appErr.Code != "CIRCUIT_OPEN" {
+		return nil, false
+	}
+
+	cotacao, err := s.repository.FindLatest(ctx, pair)
+	if err != nil {
+		return nil, false
+	}
+
+	age := time.Since(lastSeen(cotacao))
+	if age > s.staleMaxAge {
+		return nil, false
+	}
+
+	log.Printf("Servindo cotação armazenada de %s com %s de idade: %v\n", pair, age.Round(time.Second), cause)
+
+	return &CotacaoResult{Cotacao: cotacao, Stale: true, Age: age}, true
+}
+
+func (s *CotacaoService) fetchAndSave(ctx context.Context, pair models.Pair) (*models.Cotacao, error) {
+	cotacao, err := s.apiClient.Fetch(ctx, pair)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if err := s.repository.Save(ctx, cotacao); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return cotacao.Bid, nil
+	s.cache.Set(pair, cotacao)
+
+	return cotacao, nil
+}
+
+func (s *CotacaoService) CacheTTL(pair models.Pair) time.Duration {
+	return s.cache.TTL(pair)
+}
+
+func (s *CotacaoService) CacheStats() CacheStats {
+	return s.cache.Stats()
 }
 
+func (s *CotacaoService) GetCotacaoByID(ctx context.Context, id int64) (*models.Cotacao, error) {
+	return s.repository.FindByID(ctx, id)
+}
 
+func (s *CotacaoService) ListCotacoes(ctx context.Context, filter repository.ListFilter) ([]*models.Cotacao, error) {
+	return s.repository.List(ctx, filter)
+}
+
+func (s *CotacaoService) GetCandles(ctx context.Context, filter repository.CandleFilter) ([]models.Candle, error) {
+	return s.repository.Candles(ctx, filter)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type flightCall struct {
+	done    chan struct{}
+	cotacao *models.Cotacao
+	err     error
+}
+
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[models.Pair]*flightCall
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		calls: make(map[models.Pair]*flightCall),
+	}
+}
+
+func (g *flightGroup) Do(
+	ctx context.Context,
+	pair models.Pair,
+	fn func(ctx context.Context) (*models.Cotacao, error),
+) (*models.Cotacao, error) {
+	g.mu.Lock()
+	call, ok := g.calls[pair]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[pair] = call
+
+		shared, cancel := context.WithoutCancel(ctx), context.CancelFunc(func() {})
+		if deadline, ok := ctx.Deadline(); ok {
+			shared, cancel = context.WithDeadline(shared, deadline)
+		}
+
+		go func() {
+			defer cancel()
+			call.cotacao, call.err = fn(shared)
+
+			g.mu.Lock()
+			delete(g.calls, pair)
+			g.mu.Unlock()
+
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		cotacao := *call.cotacao
+		return &cotacao, nil
+	case <-ctx.Done():
+		return nil, errors.ErroTimeoutContext("obter cotação", ctx.Err())
+	}
+}
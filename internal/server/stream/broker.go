@@ -0,0 +1,107 @@
+package stream
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"client-server-api/internal/server/config"
+	"client-server-api/pkg/errors"
+	"client-server-api/pkg/models"
+)
+
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+	closed      bool
+}
+
+type Subscription struct {
+	Events chan *models.Cotacao
+
+	pairs      map[models.Pair]bool
+	overflowed atomic.Bool
+}
+
+func NewBroker(cfg config.StreamConfig) *Broker {
+	return &Broker{
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  cfg.BufferSize,
+	}
+}
+
+func (b *Broker) Subscribe(pairs []models.Pair) (*Subscription, error) {
+	sub := &Subscription{
+		Events: make(chan *models.Cotacao, b.bufferSize),
+		pairs:  make(map[models.Pair]bool, len(pairs)),
+	}
+	for _, pair := range pairs {
+		sub.pairs[pair] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, errors.ErroIndisponivel("servidor em encerramento")
+	}
+	b.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+func (b *Broker) Publish(cotacao *models.Cotacao) {
+	pair := models.Pair{Code: cotacao.Code, Codein: cotacao.Codein}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		if !sub.pairs[pair] {
+			continue
+		}
+
+		event := *cotacao
+		select {
+		case sub.Events <- &event:
+		default:
+			sub.overflowed.Store(true)
+			delete(b.subscribers, sub)
+			close(sub.Events)
+			log.Printf("Cliente de stream desconectado por excesso de eventos pendentes (%d)\n", b.bufferSize)
+		}
+	}
+}
+
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscribers)
+}
+
+func (s *Subscription) Overflowed() bool {
+	return s.overflowed.Load()
+}
@@ -79,6 +79,22 @@ func ErroNotFound(resource string) *AppError {
 	}
 }
 
+func ErroCircuitoAberto(provider string) *AppError {
+	return &AppError{
+		Code:    "CIRCUIT_OPEN",
+		Message: "Provedor de cotação indisponível: " + provider,
+		Err:     nil,
+	}
+}
+
+func ErroIndisponivel(message string) *AppError {
+	return &AppError{
+		Code:    "UNAVAILABLE",
+		Message: message,
+		Err:     nil,
+	}
+}
+
 func GetHTTPStatus(err error) int {
 	var appErr *AppError
 	if !As(err, &appErr) {
@@ -96,6 +112,8 @@ func GetHTTPStatus(err error) int {
 		return http.StatusBadRequest
 	case "NOT_FOUND":
 		return http.StatusNotFound
+	case "CIRCUIT_OPEN", "UNAVAILABLE":
+		return http.StatusServiceUnavailable
 	case "INTERNAL_ERROR":
 		return http.StatusInternalServerError
 	default:
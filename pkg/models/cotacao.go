@@ -1,15 +1,75 @@
 package models
 
-import "time"
+import (
+	"encoding/xml"
+	"time"
+)
 
 type BidResponse struct {
-	Bid string `json:"bid"`
+	XMLName   xml.Name `json:"-" xml:"cotacao"`
+	Bid       string   `json:"bid" xml:"bid"`
+	Providers []string `json:"providers,omitempty" xml:"provider,omitempty"`
+	Spread    string   `json:"spread,omitempty" xml:"spread,omitempty"`
+	Stale     bool     `json:"stale,omitempty" xml:"stale,omitempty"`
+	Age       int64    `json:"age,omitempty" xml:"age,omitempty"`
+}
+
+type CotacaoResponse struct {
+	XMLName xml.Name `json:"-" xml:"cotacao"`
+	*Cotacao
+	Stale bool  `json:"stale,omitempty" xml:"stale,omitempty"`
+	Age   int64 `json:"age,omitempty" xml:"age,omitempty"`
+}
+
+type ConversionResponse struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Amount Decimal           `json:"amount"`
+	Result Decimal           `json:"result"`
+	Rate   Decimal           `json:"rate"`
+	Quotes []ConversionQuote `json:"quotes"`
+	Stale  bool              `json:"stale,omitempty"`
+}
+
+type ConversionQuote struct {
+	ID        int64     `json:"id,omitzero"`
+	Pair      string    `json:"pair"`
+	Side      string    `json:"side"`
+	Price     Decimal   `json:"price"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+}
+
+type ParesResponse struct {
+	Pairs []string `json:"pairs"`
+}
+
+type HistoricoResponse struct {
+	XMLName    xml.Name   `json:"-" xml:"historico"`
+	Items      []*Cotacao `json:"items" xml:"cotacao"`
+	NextCursor string     `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"`
+}
+
+type Candle struct {
+	Start time.Time `json:"start"`
+	Open  Decimal   `json:"open"`
+	High  Decimal   `json:"high"`
+	Low   Decimal   `json:"low"`
+	Close Decimal   `json:"close"`
+	Count int64     `json:"count"`
+}
+
+type CandlesResponse struct {
+	Pair     string   `json:"pair"`
+	Interval string   `json:"interval"`
+	Candles  []Candle `json:"candles"`
 }
 
 type DolarResponse struct {
 	USDBRL DolarInfo `json:"USDBRL"`
 }
 
+type AwesomeAPIResponse map[string]DolarInfo
+
 type DolarInfo struct {
 	Code       string `json:"code"`
 	Codein     string `json:"codein"`
@@ -25,17 +85,21 @@ type DolarInfo struct {
 }
 
 type Cotacao struct {
-	ID         int64     `json:"id"`
-	Code       string    `json:"code"`
-	Codein     string    `json:"codein"`
-	Name       string    `json:"name"`
-	High       string    `json:"high"`
-	Low        string    `json:"low"`
-	VarBid     string    `json:"var_bid"`
-	PctChange  string    `json:"pct_change"`
-	Bid        string    `json:"bid"`
-	Ask        string    `json:"ask"`
-	Timestamp  string    `json:"timestamp"`
-	CreateDate string    `json:"create_date"`
-	CreatedAt  time.Time `json:"created_at"`
+	XMLName    xml.Name  `json:"-" xml:"cotacao"`
+	ID         int64     `json:"id,omitzero" xml:"id,omitempty"`
+	Code       string    `json:"code" xml:"code"`
+	Codein     string    `json:"codein" xml:"codein"`
+	Name       string    `json:"name" xml:"name"`
+	High       Decimal   `json:"high" xml:"high"`
+	Low        Decimal   `json:"low" xml:"low"`
+	VarBid     Decimal   `json:"var_bid" xml:"var_bid"`
+	PctChange  Decimal   `json:"pct_change" xml:"pct_change"`
+	Bid        Decimal   `json:"bid" xml:"bid"`
+	Ask        Decimal   `json:"ask" xml:"ask"`
+	Timestamp  time.Time `json:"timestamp,omitzero" xml:"timestamp"`
+	CreateDate time.Time `json:"create_date,omitzero" xml:"create_date"`
+	Provider   string    `json:"provider" xml:"provider"`
+	Spread     string    `json:"spread" xml:"spread"`
+	CreatedAt  time.Time `json:"created_at,omitzero" xml:"created_at"`
+	LastSeenAt time.Time `json:"-" xml:"-"`
 }
@@ -0,0 +1,321 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	decimalPlaces = 8
+	decimalUnit   = 100_000_000
+)
+
+type Decimal struct {
+	units  int64
+	places int
+}
+
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Decimal{}, fmt.Errorf("decimal vazio")
+	}
+
+	negative := false
+	digits := value
+	switch digits[0] {
+	case '-':
+		negative = true
+		digits = digits[1:]
+	case '+':
+		digits = digits[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, fmt.Errorf("decimal inválido: %q", value)
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return Decimal{}, fmt.Errorf("decimal inválido: %q", value)
+			}
+		}
+	}
+
+	places := len(fracPart)
+	rounded := false
+	if places > decimalPlaces {
+		rounded = fracPart[decimalPlaces] >= '5'
+		fracPart = fracPart[:decimalPlaces]
+		places = decimalPlaces
+	}
+	fracPart += strings.Repeat("0", decimalPlaces-len(fracPart))
+
+	var units int64
+	for _, c := range intPart + fracPart {
+		digit := int64(c - '0')
+		if units > (math.MaxInt64-digit)/10 {
+			return Decimal{}, fmt.Errorf("decimal fora do intervalo: %q", value)
+		}
+		units = units*10 + digit
+	}
+	if rounded {
+		if units == math.MaxInt64 {
+			return Decimal{}, fmt.Errorf("decimal fora do intervalo: %q", value)
+		}
+		units++
+	}
+	if negative {
+		units = -units
+	}
+
+	return Decimal{units: units, places: places}, nil
+}
+
+func MustParseDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func DecimalFromRat(value *big.Rat, places int) Decimal {
+	places = min(max(places, 0), decimalPlaces)
+
+	scaled := new(big.Rat).Mul(value, big.NewRat(decimalUnit, 1))
+	units := roundHalfUp(scaled)
+
+	step := int64(1)
+	for i := places; i < decimalPlaces; i++ {
+		step *= 10
+	}
+	units = roundHalfUp(big.NewRat(units, step)) * step
+
+	return Decimal{units: units, places: places}
+}
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundDown     RoundingMode = "down"
+	RoundUp       RoundingMode = "up"
+)
+
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("modo de arredondamento inválido: %q", value)
+	}
+}
+
+func RoundDecimal(value *big.Rat, places int, mode RoundingMode) (Decimal, error) {
+	places = min(max(places, 0), decimalPlaces)
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(scale))
+
+	num := new(big.Int).Abs(scaled.Num())
+	quo, rem := new(big.Int).QuoRem(num, scaled.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		half := new(big.Int).Mul(rem, big.NewInt(2)).Cmp(scaled.Denom())
+		switch mode {
+		case RoundUp:
+			quo.Add(quo, big.NewInt(1))
+		case RoundHalfUp:
+			if half >= 0 {
+				quo.Add(quo, big.NewInt(1))
+			}
+		case RoundHalfEven:
+			if half > 0 || (half == 0 && quo.Bit(0) == 1) {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+	if scaled.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	step := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimalPlaces-places)), nil)
+	units := quo.Mul(quo, step)
+	if !units.IsInt64() {
+		return Decimal{}, fmt.Errorf("decimal fora do intervalo: %s", value.FloatString(decimalPlaces))
+	}
+
+	return Decimal{units: units.Int64(), places: places}, nil
+}
+
+func roundHalfUp(value *big.Rat) int64 {
+	num := new(big.Int).Abs(value.Num())
+	quo, rem := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	return quo.Int64()
+}
+
+func (d Decimal) Rat() *big.Rat {
+	return big.NewRat(d.units, decimalUnit)
+}
+
+func (d Decimal) Places() int {
+	if d.places < 0 {
+		return d.trimmedPlaces()
+	}
+	return d.places
+}
+
+func (d Decimal) WithPlaces(places int) Decimal {
+	return DecimalFromRat(d.Rat(), places)
+}
+
+func (d Decimal) Trim() Decimal {
+	d.places = -1
+	return d
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	switch {
+	case d.units < other.units:
+		return -1
+	case d.units > other.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{units: d.units - other.units, places: max(d.Places(), other.Places())}
+}
+
+func (d Decimal) Abs() Decimal {
+	if d.units < 0 {
+		d.units = -d.units
+	}
+	return d
+}
+
+func (d Decimal) Sign() int {
+	return d.Cmp(Decimal{})
+}
+
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / decimalUnit
+}
+
+func (d Decimal) String() string {
+	places := d.Places()
+
+	units := d.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	intPart := units / decimalUnit
+	if places == 0 {
+		return sign + strconv.FormatInt(intPart, 10)
+	}
+
+	frac := fmt.Sprintf("%08d", units%decimalUnit)
+	return sign + strconv.FormatInt(intPart, 10) + "." + frac[:places]
+}
+
+func (d Decimal) trimmedPlaces() int {
+	frac := d.units % decimalUnit
+	if frac < 0 {
+		frac = -frac
+	}
+	if frac == 0 {
+		return 0
+	}
+
+	places := decimalPlaces
+	for frac%10 == 0 {
+		frac /= 10
+		places--
+	}
+	return places
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		value = string(data)
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.units, nil
+}
+
+func (d *Decimal) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*d = Decimal{}
+	case int64:
+		*d = Decimal{units: value, places: -1}
+	case float64:
+		*d = DecimalFromRat(new(big.Rat).SetFloat64(value), decimalPlaces)
+		d.places = -1
+	case []byte:
+		return d.scanString(string(value))
+	case string:
+		return d.scanString(value)
+	default:
+		return fmt.Errorf("tipo incompatível com Decimal: %T", src)
+	}
+
+	return nil
+}
+
+func (d *Decimal) scanString(value string) error {
+	if value == "" {
+		*d = Decimal{}
+		return nil
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
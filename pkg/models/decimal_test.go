@@ -0,0 +1,86 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"5.4320", "5.4320"},
+		{"-0.0010", "-0.0010"},
+		{"+1.5", "1.5"},
+		{".5", "0.5"},
+		{"7.", "7"},
+		{"0", "0"},
+		{"000.000", "0.000"},
+		{"9999999999", "9999999999"},
+		{"92233720368.54775807", "92233720368.54775807"},
+		{"-92233720368.54775807", "-92233720368.54775807"},
+		{"1.123456785", "1.12345679"},
+		{" 2.5 ", "2.5"},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.value)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", tt.value, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, esperado %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseDecimalRejects(t *testing.T) {
+	for _, value := range []string{"", "  ", "-", ".", "1.2.3", "abc", "1e5", "92233720368.54775808", "92233720368.547758075"} {
+		if got, err := ParseDecimal(value); err == nil {
+			t.Errorf("ParseDecimal(%q) = %s, esperado erro", value, got)
+		}
+	}
+}
+
+func TestRoundDecimal(t *testing.T) {
+	tests := []struct {
+		value  string
+		places int
+		mode   RoundingMode
+		want   string
+	}{
+		{"1.005", 2, RoundHalfUp, "1.01"},
+		{"1.005", 2, RoundHalfEven, "1.00"},
+		{"1.015", 2, RoundHalfEven, "1.02"},
+		{"1.005", 2, RoundDown, "1.00"},
+		{"1.005", 2, RoundUp, "1.01"},
+		{"1.001", 2, RoundHalfUp, "1.00"},
+		{"1.001", 2, RoundUp, "1.01"},
+		{"1.009", 2, RoundDown, "1.00"},
+		{"2.5", 0, RoundHalfEven, "2"},
+		{"3.5", 0, RoundHalfEven, "4"},
+		{"1.20", 2, RoundUp, "1.20"},
+		{"-1.005", 2, RoundHalfUp, "-1.01"},
+		{"-1.005", 2, RoundHalfEven, "-1.00"},
+		{"-1.015", 2, RoundHalfEven, "-1.02"},
+		{"-1.009", 2, RoundDown, "-1.00"},
+		{"-1.001", 2, RoundUp, "-1.01"},
+		{"1/3", 8, RoundHalfUp, "0.33333333"},
+		{"2/3", 8, RoundDown, "0.66666666"},
+	}
+	for _, tt := range tests {
+		value, ok := new(big.Rat).SetString(tt.value)
+		if !ok {
+			t.Fatalf("valor de teste inválido: %s", tt.value)
+		}
+		got, err := RoundDecimal(value, tt.places, tt.mode)
+		if err != nil {
+			t.Errorf("RoundDecimal(%s, %d, %s): %v", tt.value, tt.places, tt.mode, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("RoundDecimal(%s, %d, %s) = %s, esperado %s", tt.value, tt.places, tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cotacaoCSVHeader = []string{
+	"id", "code", "codein", "name", "high", "low", "var_bid", "pct_change", "bid", "ask",
+	"timestamp", "create_date", "provider", "spread", "created_at",
+}
+
+func (c *Cotacao) CSVRecords() [][]string {
+	return [][]string{cotacaoCSVHeader, c.csvRow()}
+}
+
+func (c *Cotacao) PlainText() string {
+	return plainFields(cotacaoCSVHeader, c.csvRow())
+}
+
+func (c *Cotacao) csvRow() []string {
+	id := ""
+	if c.ID != 0 {
+		id = strconv.FormatInt(c.ID, 10)
+	}
+
+	return []string{
+		id, c.Code, c.Codein, c.Name,
+		c.High.String(), c.Low.String(), c.VarBid.String(), c.PctChange.String(), c.Bid.String(), c.Ask.String(),
+		formatTime(c.Timestamp), formatTime(c.CreateDate), c.Provider, c.Spread, formatTime(c.CreatedAt),
+	}
+}
+
+func (r CotacaoResponse) CSVRecords() [][]string {
+	header := append(append([]string{}, cotacaoCSVHeader...), "stale", "age")
+	return [][]string{header, r.csvRow()}
+}
+
+func (r CotacaoResponse) PlainText() string {
+	header := append(append([]string{}, cotacaoCSVHeader...), "stale", "age")
+	return plainFields(header, r.csvRow())
+}
+
+func (r CotacaoResponse) csvRow() []string {
+	row := r.Cotacao.csvRow()
+	if !r.Stale {
+		return append(row, "", "")
+	}
+	return append(row, "true", strconv.FormatInt(r.Age, 10))
+}
+
+func (r BidResponse) CSVRecords() [][]string {
+	row := []string{r.Bid, strings.Join(r.Providers, ","), r.Spread, "", ""}
+	if r.Stale {
+		row[3], row[4] = "true", strconv.FormatInt(r.Age, 10)
+	}
+	return [][]string{{"bid", "providers", "spread", "stale", "age"}, row}
+}
+
+func (r BidResponse) PlainText() string {
+	return r.Bid + "\n"
+}
+
+func (r HistoricoResponse) CSVRecords() [][]string {
+	records := make([][]string, 0, len(r.Items)+1)
+	records = append(records, cotacaoCSVHeader)
+	for _, cotacao := range r.Items {
+		records = append(records, cotacao.csvRow())
+	}
+	return records
+}
+
+func (r HistoricoResponse) PlainText() string {
+	var b strings.Builder
+	for _, record := range r.CSVRecords() {
+		b.WriteString(strings.Join(record, "\t"))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func plainFields(names, values []string) string {
+	var b strings.Builder
+	for i, name := range names {
+		if values[i] == "" {
+			continue
+		}
+		b.WriteString(name + ": " + values[i] + "\n")
+	}
+	return b.String()
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strings"
+
+	"client-server-api/pkg/errors"
+)
+
+type Pair struct {
+	Code   string
+	Codein string
+}
+
+var DefaultPair = Pair{Code: "USD", Codein: "BRL"}
+
+func ParsePair(value string) (Pair, error) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(value)), "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Pair{}, errors.ErroValidacao("par de moedas inválido: " + value)
+	}
+
+	pair := Pair{Code: parts[0], Codein: parts[1]}
+	if !IsCurrency(pair.Code) || !IsCurrency(pair.Codein) {
+		return Pair{}, errors.ErroValidacao("moeda desconhecida no par: " + value)
+	}
+	if pair.Code == pair.Codein {
+		return Pair{}, errors.ErroValidacao("par de moedas inválido: " + value)
+	}
+
+	return pair, nil
+}
+
+func (p Pair) String() string {
+	return p.Code + "-" + p.Codein
+}
+
+func (p Pair) Key() string {
+	return p.Code + p.Codein
+}
+
+func ParsePairs(value string) ([]Pair, error) {
+	var pairs []Pair
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		pair, err := ParsePair(item)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+const CreateDateLayout = "2006-01-02 15:04:05"
+
+var SaoPaulo = MustLoadLocation("America/Sao_Paulo")
+
+func MustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+func ParseUnixTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp inválido: %q", value)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+func ParseCreateDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.ParseInLocation(CreateDateLayout, value, SaoPaulo)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("create_date inválido: %q", value)
+	}
+
+	return parsed, nil
+}